@@ -8,16 +8,48 @@ type Enum struct {
 	// Nested names will concat with their parent messages so that it will remain unique
 	// This also means nested type might be a bit ugly in type script but whatever
 	Name string
+	// Namespace is the namespace the enum is declared in, e.g. Outer.Middle, it is empty unless nested
+	// types are rendered in namespaces
+	Namespace string
+	// FQType is the fully qualified type name of the enum
+	FQType string
 	// Due to the fact that Protos allows alias fields which is not a feature
 	// in Typescript, it's better to use string representation of it.
 	// So Values here will basically be the name of the field.
 	Values []string
+	// Numbers holds the number of each value, in the same order as Values
+	Numbers []int32
+	// Keys holds the typescript key of each value, in the same order as Values
+	Keys []string
+	// DisplayNames holds the display_name option of each value, in the same order as Values
+	DisplayNames []string
+	// Descriptions holds the description option of each value, or its leading comment, in the same
+	// order as Values
+	Descriptions []string
+	// Hidden holds the hidden option of each value, in the same order as Values
+	Hidden []bool
+}
+
+// IsAlias returns whether the value at index i shares its number with a previous value, which
+// requires the allow_alias option.
+func (e *Enum) IsAlias(i int) bool {
+	for j := 0; j < i; j++ {
+		if e.Numbers[j] == e.Numbers[i] {
+			return true
+		}
+	}
+	return false
 }
 
 // NewEnum creates an enum instance.
 func NewEnum() *Enum {
 	return &Enum{
-		Name:   "",
-		Values: make([]string, 0),
+		Name:         "",
+		Values:       make([]string, 0),
+		Numbers:      make([]int32, 0),
+		Keys:         make([]string, 0),
+		DisplayNames: make([]string, 0),
+		Descriptions: make([]string, 0),
+		Hidden:       make([]bool, 0),
 	}
 }
@@ -29,6 +29,12 @@ type File struct {
 	// file, which will be used to figure out external dependencies inside the
 	// same package (different files)
 	PackageNonScalarType []Type
+	// Resources are the resources declared in the file with google.api.resource and
+	// google.api.resource_definition
+	Resources []*Resource
+	// ResourceReferences stores the references to resources made by the fields of the file, which
+	// will be used to figure out the files declaring them once every file is analysed
+	ResourceReferences []*ResourceReference
 	// Dependencies is a list of dependencies for the file, which will be rendered
 	// at the top of the file as import statements
 	dependencies []*Dependency
@@ -49,6 +55,10 @@ func (f *File) Dependencies() []*Dependency {
 	out := make([]*Dependency, len(f.dependencies))
 	copy(out, f.dependencies)
 	sort.Slice(out, func(i, j int) bool {
+		if out[i].SourceFile == out[j].SourceFile {
+			// a module imported as a whole comes before the types imported from it
+			return len(out[i].TypeNames) < len(out[j].TypeNames)
+		}
 		return out[i].SourceFile < out[j].SourceFile
 	})
 	return out
@@ -94,8 +104,35 @@ func (f *File) TrackPackageNonScalarType(t Type) {
 	}
 }
 
+// NestedMessages returns the messages declared in a namespace, the top level one being empty.
+func (f *File) NestedMessages(namespace string) []*Message {
+	messages := make([]*Message, 0)
+	for _, m := range f.Messages {
+		if m.Namespace == namespace {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// NestedEnums returns the enums declared in a namespace, the top level one being empty.
+func (f *File) NestedEnums(namespace string) []*Enum {
+	enums := make([]*Enum, 0)
+	for _, e := range f.Enums {
+		if e.Namespace == namespace {
+			enums = append(enums, e)
+		}
+	}
+	return enums
+}
+
+// HasNestedTypes returns whether messages or enums are declared in the namespace of a message.
+func (f *File) HasNestedTypes(m *Message) bool {
+	return len(f.NestedMessages(m.QualifiedName())) > 0 || len(f.NestedEnums(m.QualifiedName())) > 0
+}
+
 func (f *File) IsEmpty() bool {
-	return len(f.Enums) == 0 && len(f.Messages) == 0 && len(f.Services) == 0
+	return len(f.Enums) == 0 && len(f.Messages) == 0 && len(f.Services) == 0 && len(f.Resources) == 0
 }
 
 // NewFile returns an initialised new file.
@@ -109,6 +146,24 @@ func NewFile() *File {
 	}
 }
 
+// Resource stores the information about rendering the helpers of a resource name.
+type Resource struct {
+	// Type is the resource type, e.g. library.googleapis.com/Book
+	Type string
+	// Name is the name of the branded type of the resource names, e.g. BookName
+	Name string
+	// Patterns are the patterns of the resource names, e.g. projects/{project}/books/{book}
+	Patterns []string
+}
+
+// ResourceReference is a reference to a resource made by a field.
+type ResourceReference struct {
+	// Type is the resource type
+	Type string
+	// IsExternal indicates whether the resource is declared in another file
+	IsExternal bool
+}
+
 // Dependency stores the information about dependencies.
 type Dependency struct {
 	// ModuleIdentifier will be a concanation of package + file base name to make it
@@ -117,6 +172,9 @@ type Dependency struct {
 	ModuleIdentifier string
 	// Source file will be the file at the end of the import statement.
 	SourceFile string
+	// TypeNames are the types imported from the source file for the ts_type option of fields, the
+	// source file is then imported with import type rather than as a module
+	TypeNames []string
 }
 
 // GetModuleName returns module name = package name + base file name to be the
@@ -131,6 +189,41 @@ func GetModuleName(packageName, fileName string) string {
 	return strcase.ToCamel(packageName) + strcase.ToCamel(name)
 }
 
+// FunctionCase takes a service name or method name in the form SomeMethod or
+// HTTPMethod and returns someMethod or httpMethod.
+func FunctionCase(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+
+	// Find the position of the first non-uppercase letter after the initial uppercase sequence
+	firstLowerPos := -1
+	for i := range len(s) {
+		if 'a' <= s[i] && s[i] <= 'z' {
+			firstLowerPos = i
+			break
+		}
+	}
+
+	switch firstLowerPos {
+	case -1:
+		// If no lowercase letter is found, return the string in lowercase
+		return strings.ToLower(s)
+
+	case 0:
+		// If the first letter is lowercase, return the string as is.
+		return s
+
+	case 1:
+		// If only the first letter is upper, we want to lowercase it.
+		return strings.ToLower(s[:1]) + s[1:]
+
+	default:
+		// If multiple letters are upper case, we want all but the last one.
+		return strings.ToLower(s[:firstLowerPos-1]) + s[firstLowerPos-1:]
+	}
+}
+
 // GetTSFileName gets the typescript filename out of the proto file name.
 func GetTSFileName(fileName string) string {
 	baseName := filepath.Base(fileName)
@@ -157,4 +250,6 @@ type TypeInfo struct {
 	IsExternal bool
 	// Indicates whether the field is optional
 	IsOptional bool
+	// Variant is the suffix of the message variant to render, e.g. Input, when the type has one
+	Variant string
 }
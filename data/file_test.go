@@ -25,3 +25,25 @@ func TestGetModuleName(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionCase(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"upper camel case", "SomeMethod", "someMethod"},
+		{"leading acronym", "HTTPMethod", "httpMethod"},
+		{"all upper case", "GET", "get"},
+		{"lower camel case", "someMethod", "someMethod"},
+		{"underscore", "Library_Get", "library_Get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FunctionCase(tt.input); got != tt.want {
+				t.Errorf("FunctionCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,9 @@ type Message struct {
 	Nested bool
 	// Name is the name of the Message
 	Name string
+	// Namespace is the namespace the message is declared in, e.g. Outer.Middle, it is empty unless
+	// nested types are rendered in namespaces
+	Namespace string
 	// Message has been marked as deprecated
 	IsDeprecated bool
 	// FQType is the fully qualified type name for the message itself
@@ -26,6 +29,23 @@ type Message struct {
 	// OneOfFieldNames is the names of one of fields with same index. so that
 	// renderer can render the clearing of other fields on set.
 	OneOfFieldsNames map[int32]string
+	// RequiredOneOfs contains the indexes of the one of groups in which a field must be set
+	RequiredOneOfs map[int32]bool
+	// IsValidationDisabled indicates the constraints of the message are turned off
+	IsValidationDisabled bool
+	// IsReadonly indicates the fields of the message are declared readonly
+	IsReadonly bool
+	// IsInterface indicates the message is declared as an interface rather than a type
+	IsInterface bool
+}
+
+// QualifiedName returns the name of the message prefixed with its namespace, which is also the
+// namespace of the types nested in the message.
+func (m *Message) QualifiedName() string {
+	if m.Namespace == "" {
+		return m.Name
+	}
+	return m.Namespace + "." + m.Name
 }
 
 // HasOneOfFields returns true when the message has a one of field.
@@ -69,6 +89,7 @@ func NewMessage() *Message {
 		Messages:          make([]*Message, 0),
 		OneOfFieldsGroups: make(map[int32][]*Field),
 		OneOfFieldsNames:  make(map[int32]string),
+		RequiredOneOfs:    make(map[int32]bool),
 	}
 }
 
@@ -110,6 +131,45 @@ type Field struct {
 	OneOfIndex int32
 	// IsRepeated indicates whether the field is a repeated field
 	IsRepeated bool
+	// IsRequired indicates the field is declared as required by its validation rules
+	IsRequired bool
+	// IgnoreEmpty indicates the validation rules don't apply when the field is empty
+	IgnoreEmpty bool
+	// Constraints are the validation rules of the field
+	Constraints []*Constraint
+	// ItemConstraints are the validation rules of each item of a repeated field
+	ItemConstraints []*Constraint
+	// IsInputRequired indicates the field is marked REQUIRED with google.api.field_behavior, it must
+	// be set in requests
+	IsInputRequired bool
+	// IsOutputOnly indicates the field is marked OUTPUT_ONLY, it is only set in responses
+	IsOutputOnly bool
+	// IsInputOnly indicates the field is marked INPUT_ONLY, it is only set in requests
+	IsInputOnly bool
+	// IsImmutable indicates the field is marked IMMUTABLE, it can only be set once
+	IsImmutable bool
+	// Variant is the suffix of the message variant the field is rendered in, e.g. Input, empty for
+	// the message itself
+	Variant string
+	// Resource is set when the field holds the name of a resource, either with
+	// google.api.resource_reference or as the name field of a resource
+	Resource *ResourceReference
+	// TSType is the TypeScript type of the field set with the ts_type option, replacing the type of
+	// each value of repeated fields
+	TSType string
+}
+
+// HasConstraints returns true when validation rules are declared on the field.
+func (f *Field) HasConstraints() bool {
+	return f.IsRequired || len(f.Constraints) > 0 || len(f.ItemConstraints) > 0
+}
+
+// Constraint is a validation rule declared on a field with buf.validate or protoc-gen-validate.
+type Constraint struct {
+	// ID identifies the rule the way protovalidate does, e.g. string.min_len
+	ID string
+	// Value is the argument of the rule, a bool, a number, a string or a list of numbers or strings
+	Value interface{}
 }
 
 // GetType returns some information of the type to aid the rendering.
@@ -119,6 +179,7 @@ func (f *Field) GetType() *TypeInfo {
 		IsRepeated: f.IsRepeated,
 		IsExternal: f.IsExternal,
 		IsOptional: f.IsOptional,
+		Variant:    f.Variant,
 	}
 }
 
@@ -4,6 +4,9 @@ package data
 type Service struct {
 	// Name is the name of the Service
 	Name string
+	// ClientName is the name of the class holding the methods of the service, the name of the service
+	// or, with use_static_classes=false, the name followed by Client, unless set with ts_service
+	ClientName string
 	// Methods is a list of methods data
 	Methods []*Method
 }
@@ -70,6 +73,23 @@ type Method struct {
 	BindingIndex int
 	// TSMethodName is the generated TypeScript method name
 	TSMethodName string
+	// Pagination is set when the request and response of the method follow AIP-158
+	Pagination *Pagination
+	// PathPrefix is the path prefix of the requests when the InitReq doesn't set one, e.g. /api
+	PathPrefix string
+	// StreamingStyle is how the responses of a server streaming method are delivered, callback or
+	// async_iterable
+	StreamingStyle string
+}
+
+// Pagination describes the fields used to page through the results of a List method.
+type Pagination struct {
+	// PageTokenField is the field of the request holding the token of the page to fetch
+	PageTokenField string
+	// NextPageTokenField is the field of the response holding the token of the next page
+	NextPageTokenField string
+	// ItemsField is the repeated field of the response holding the items of a page
+	ItemsField string
 }
 
 // MethodArgument stores the type information about method argument.
@@ -80,6 +100,18 @@ type MethodArgument struct {
 	IsExternal bool
 	// IsRepeated indicates whether the field is a repeated field
 	IsRepeated bool
+	// Operation is set when the argument is a google.longrunning.Operation annotated with the types
+	// of its response and metadata
+	Operation *Operation
+}
+
+// Operation holds the types declared with google.longrunning.operation_info, either of them is nil
+// when it isn't declared or can't be found.
+type Operation struct {
+	// ResponseType is the type of the response of the operation once it is done
+	ResponseType *MethodArgument
+	// MetadataType is the type of the metadata of the operation
+	MetadataType *MethodArgument
 }
 
 // GetType returns some information of the type to aid the rendering.
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// Suffixes of the message variants rendered for google.api.field_behavior.
+const (
+	// inputVariant is sent in requests, output only fields are dropped and required fields can't
+	// be omitted.
+	inputVariant = "Input"
+	// outputVariant is received in responses, input only fields are dropped.
+	outputVariant = "Output"
+)
+
+// hasVariant returns whether a message has the given variant, which is the case when a field it
+// changes can be reached from the message.
+func hasVariant(r *registry.Registry, protoType, variant string) bool {
+	return reachesVariantField(r, protoType, variant, make(map[string]bool))
+}
+
+func reachesVariantField(r *registry.Registry, protoType, variant string, visited map[string]bool) bool {
+	if visited[protoType] {
+		return false
+	}
+	visited[protoType] = true
+
+	typeInfo, ok := r.Types[protoType]
+	if !ok {
+		return false
+	}
+	if typeInfo.IsMapEntry {
+		return reachesVariantField(r, typeInfo.ValueType.Type, variant, visited)
+	}
+	if typeInfo.Message == nil {
+		return false
+	}
+	for _, f := range typeInfo.Message.Fields {
+		if isVariantField(f, variant) {
+			return true
+		}
+	}
+	for _, f := range typeInfo.Message.Fields {
+		if reachesVariantField(r, f.Type, variant, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVariantField returns whether the field is dropped or changed by the variant.
+func isVariantField(f *data.Field, variant string) bool {
+	switch variant {
+	case inputVariant:
+		return f.IsOutputOnly || isRequiredKey(f, variant)
+	case outputVariant:
+		return f.IsInputOnly
+	default:
+		return false
+	}
+}
+
+// isRequiredKey returns whether the field must be set in the variant, fields of a oneof can't be.
+func isRequiredKey(f *data.Field, variant string) bool {
+	return variant == inputVariant && f.IsInputRequired && !f.IsOneOfField
+}
+
+// messageVariant returns a copy of the message rendered as the given variant, or nil when the
+// message has no such variant.
+func messageVariant(r *registry.Registry) func(msg *data.Message, variant string) *data.Message {
+	return func(msg *data.Message, variant string) *data.Message {
+		if !hasVariant(r, msg.FQType, variant) {
+			return nil
+		}
+		v := data.NewMessage()
+		v.Name = msg.Name + variant
+		v.FQType = msg.FQType
+		v.IsDeprecated = msg.IsDeprecated
+		v.IsReadonly = msg.IsReadonly
+		v.IsInterface = msg.IsInterface
+		v.OneOfFieldsNames = msg.OneOfFieldsNames
+		for _, f := range msg.Fields {
+			if (variant == inputVariant && f.IsOutputOnly) || (variant == outputVariant && f.IsInputOnly) {
+				continue
+			}
+			field := *f
+			field.Variant = variant
+			field.Message = v
+			v.Fields = append(v.Fields, &field)
+			if !field.IsOneOfField {
+				v.NonOneOfFields = append(v.NonOneOfFields, &field)
+			}
+			if field.IsOptional {
+				v.OptionalFields = append(v.OptionalFields, &field)
+			}
+			if field.IsOneOfField && !field.IsOptional {
+				v.OneOfFieldsGroups[field.OneOfIndex] = append(v.OneOfFieldsGroups[field.OneOfIndex], &field)
+			}
+		}
+		return v
+	}
+}
+
+// tsVariantType returns the typescript type of a method argument rendered as the given variant.
+func tsVariantType(r *registry.Registry, variant string) func(arg *data.MethodArgument) string {
+	return func(arg *data.MethodArgument) string {
+		if arg.Operation != nil && variant == outputVariant {
+			return operationType(r, arg.Operation)
+		}
+		info := arg.GetType()
+		info.Variant = variant
+		return tsTypeInfo(r, info)
+	}
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestMessageVariant(t *testing.T) {
+	author := data.NewMessage()
+	author.Name = "Author"
+	author.FQType = ".test.Author"
+	author.Fields = []*data.Field{
+		{Name: "id", Type: "string", IsOutputOnly: true},
+		{Name: "display_name", Type: "string"},
+	}
+
+	book := data.NewMessage()
+	book.Name = "Book"
+	book.FQType = ".test.Book"
+	book.Fields = []*data.Field{
+		{Name: "title", Type: "string", IsInputRequired: true},
+		{Name: "secret", Type: "string", IsInputOnly: true},
+		{Name: "author", Type: ".test.Author"},
+		{Name: "related", Type: ".test.Book", IsRepeated: true},
+	}
+
+	plain := data.NewMessage()
+	plain.Name = "Plain"
+	plain.FQType = ".test.Plain"
+	plain.Fields = []*data.Field{{Name: "plain", Type: ".test.Plain"}}
+
+	r := &registry.Registry{
+		Types: map[string]*registry.TypeInformation{
+			".test.Author": {PackageIdentifier: "Author", ProtoType: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, Message: author},
+			".test.Book":   {PackageIdentifier: "Book", ProtoType: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, Message: book},
+			".test.Plain":  {PackageIdentifier: "Plain", ProtoType: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, Message: plain},
+		},
+	}
+	render := func(msg *data.Message) []string {
+		fields := make([]string, 0)
+		for _, f := range msg.Fields {
+			fields = append(fields, tsTypeKey(r)(f)+": "+tsTypeDef(r)(f))
+		}
+		return fields
+	}
+
+	assert.Nil(t, messageVariant(r)(plain, inputVariant))
+	assert.Nil(t, messageVariant(r)(author, outputVariant))
+
+	input := messageVariant(r)(book, inputVariant)
+	assert.Equal(t, "BookInput", input.Name)
+	assert.Equal(t, []string{
+		"title: string",
+		"secret?: string",
+		"author?: AuthorInput",
+		"related?: BookInput[]",
+	}, render(input))
+
+	output := messageVariant(r)(book, outputVariant)
+	assert.Equal(t, "BookOutput", output.Name)
+	assert.Equal(t, []string{
+		"title?: string",
+		"author?: Author",
+		"related?: BookOutput[]",
+	}, render(output))
+
+	arg := &data.MethodArgument{Type: ".test.Author"}
+	assert.Equal(t, "AuthorInput", tsVariantType(r, inputVariant)(arg))
+	assert.Equal(t, "Author", tsVariantType(r, outputVariant)(arg))
+}
@@ -0,0 +1,206 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// fieldConstraints returns the fm.FieldConstraints literal of a field, or an empty string when
+// nothing has to be checked on the field.
+func fieldConstraints(r *registry.Registry) func(field *data.Field) string {
+	return func(field *data.Field) string {
+		typeInfo, ok := r.Types[field.Type]
+		isMap := ok && typeInfo.IsMapEntry
+
+		var validator string
+		if isMap {
+			validator = messageValidator(r, typeInfo.ValueType.GetType())
+		} else {
+			validator = messageValidator(r, field.GetType())
+		}
+		if !field.HasConstraints() && validator == "" {
+			return ""
+		}
+
+		props := make([]string, 0)
+		if field.IsRequired {
+			props = append(props, "required: true")
+		}
+		if field.IgnoreEmpty {
+			props = append(props, "ignoreEmpty: true")
+		}
+		if zero, ok := zeroValue(r, field); ok && field.HasConstraints() {
+			props = append(props, "zero: "+zero)
+		}
+		if isMap {
+			props = append(props, "map: true")
+		} else if field.IsRepeated {
+			props = append(props, "repeated: true")
+		}
+		if len(field.Constraints) > 0 {
+			props = append(props, "rules: "+renderConstraints(r, field, field.Constraints))
+		}
+		if len(field.ItemConstraints) > 0 {
+			props = append(props, "items: "+renderConstraints(r, field, field.ItemConstraints))
+		}
+		if validator != "" {
+			props = append(props, "message: "+validator)
+		}
+		return "{ " + strings.Join(props, ", ") + " }"
+	}
+}
+
+// requiredOneOfs returns the list of the oneofs of a message in which a field must be set, along with
+// the names of their fields, or an empty string if there are none.
+func requiredOneOfs(r *registry.Registry) func(msg *data.Message) string {
+	fieldNameFn := fieldName(r)
+	return func(msg *data.Message) string {
+		indexes := make([]int, 0, len(msg.RequiredOneOfs))
+		for idx := range msg.RequiredOneOfs {
+			indexes = append(indexes, int(idx))
+		}
+		if len(indexes) == 0 {
+			return ""
+		}
+		sort.Ints(indexes)
+
+		groups := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			//nolint:gosec // G115: oneof indexes originate from int32 values
+			index := int32(idx)
+			names := make([]string, 0)
+			for _, f := range msg.OneOfFieldsGroups[index] {
+				names = append(names, fmt.Sprintf("%q", fieldNameFn(f.Name)))
+			}
+			groups = append(groups, fmt.Sprintf("[%q, [%s]]", fieldNameFn(msg.OneOfFieldsNames[index]), strings.Join(names, ", ")))
+		}
+		return "[" + strings.Join(groups, ", ") + "]"
+	}
+}
+
+// messageValidator returns the validate function of a message type, or an empty string for other
+// types.
+func messageValidator(r *registry.Registry, info *data.TypeInfo) string {
+	return messageFunction(r, info, "validate")
+}
+
+// messageFunction returns the function generated for a message type with the given prefix, e.g.
+// validate, or an empty string for other types.
+func messageFunction(r *registry.Registry, info *data.TypeInfo, prefix string) string {
+	if isWellKnownType(info.Type) {
+		return ""
+	}
+	typeInfo, ok := r.Types[info.Type]
+	if !ok || typeInfo.ProtoType != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || typeInfo.IsMapEntry {
+		return ""
+	}
+	module, identifier, _ := typeReference(r, info)
+	return module + prefix + identifier
+}
+
+// zeroValue returns the value the gateway gives to an absent field, fields which track presence have
+// none.
+func zeroValue(r *registry.Registry, field *data.Field) (string, bool) {
+	if field.IsOptional || field.IsOneOfField {
+		return "", false
+	}
+	if typeInfo, ok := r.Types[field.Type]; ok && typeInfo.IsMapEntry {
+		return "{}", true
+	}
+	if field.IsRepeated {
+		return "[]", true
+	}
+	switch field.Type {
+	case "string":
+		return `""`, true
+	case "uint64", "sint64", "int64", "fixed64", "sfixed64":
+		return `"0"`, true
+	case "float", "double", "int32", "sint32", "uint32", "fixed32", "sfixed32":
+		return "0", true
+	case "bool":
+		return "false", true
+	}
+	if typeInfo, ok := r.Types[field.Type]; ok && typeInfo.EnumValues != nil {
+		if name, ok := typeInfo.EnumValues[0]; ok {
+			return enumLiteral(r, name, 0), true
+		}
+	}
+	return "", false
+}
+
+// renderConstraints renders a list of fm.Constraint, the numbers used by enum rules are replaced by
+// the names of the values unless enums are serialized as numbers.
+func renderConstraints(r *registry.Registry, field *data.Field, constraints []*data.Constraint) string {
+	rendered := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		value := c.Value
+		if typeInfo, ok := r.Types[field.Type]; ok && typeInfo.EnumValues != nil && strings.HasPrefix(c.ID, "enum.") {
+			value = enumConstraintValue(typeInfo.EnumValues, c, r.UseEnumNumbers)
+		}
+		rendered = append(rendered, fmt.Sprintf("[%q, %s]", c.ID, constraintLiteral(value)))
+	}
+	return "[" + strings.Join(rendered, ", ") + "]"
+}
+
+// constraintLiteral renders the argument of a rule as a typescript literal.
+func constraintLiteral(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, 0, len(list))
+		for _, v := range list {
+			items = append(items, constraintLiteral(v))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	literal, err := json.Marshal(value)
+	if err != nil {
+		return "undefined"
+	}
+	return string(literal)
+}
+
+func enumConstraintValue(values map[int32]string, c *data.Constraint, useNumbers bool) interface{} {
+	if c.ID == "enum.defined_only" {
+		numbers := make([]int, 0, len(values))
+		for n := range values {
+			numbers = append(numbers, int(n))
+		}
+		sort.Ints(numbers)
+		names := make([]interface{}, 0, len(numbers))
+		for _, n := range numbers {
+			if useNumbers {
+				names = append(names, n)
+				continue
+			}
+			//nolint:gosec // G115: enum numbers originate from int32 values
+			names = append(names, values[int32(n)])
+		}
+		return names
+	}
+	if useNumbers {
+		return c.Value
+	}
+	enumName := func(v interface{}) interface{} {
+		if n, ok := v.(int64); ok {
+			//nolint:gosec // G115: enum rules are int32 values
+			if name, ok := values[int32(n)]; ok {
+				return name
+			}
+		}
+		return v
+	}
+	if list, ok := c.Value.([]interface{}); ok {
+		names := make([]interface{}, 0, len(list))
+		for _, v := range list {
+			names = append(names, enumName(v))
+		}
+		return names
+	}
+	return enumName(c.Value)
+}
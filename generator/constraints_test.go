@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFieldConstraints(t *testing.T) {
+	r := &registry.Registry{
+		Types: map[string]*registry.TypeInformation{
+			".test.Msg": {
+				PackageIdentifier: "Msg",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+			},
+			".test.Color": {
+				PackageIdentifier: "Color",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+				EnumValues:        map[int32]string{0: "UNKNOWN", 2: "BLUE", 1: "RED"},
+			},
+			".test.Msg.ByIdEntry": {
+				IsMapEntry: true,
+				KeyType:    &data.MapEntryType{Type: "string"},
+				ValueType:  &data.MapEntryType{Type: ".test.Msg"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		field *data.Field
+		want  string
+	}{
+		{
+			name:  "no constraints",
+			field: &data.Field{Name: "name", Type: "string"},
+			want:  "",
+		},
+		{
+			name: "string",
+			field: &data.Field{Name: "name", Type: "string", IsRequired: true, Constraints: []*data.Constraint{
+				{ID: "string.min_len", Value: uint64(3)},
+				{ID: "string.pattern", Value: "^[a-z]+$"},
+			}},
+			want: `{ required: true, zero: "", rules: [["string.min_len", 3], ["string.pattern", "^[a-z]+$"]] }`,
+		},
+		{
+			name: "optional int64",
+			field: &data.Field{Name: "id", Type: "int64", IsOptional: true, Constraints: []*data.Constraint{
+				{ID: "int64.in", Value: []interface{}{int64(1), int64(2)}},
+			}},
+			want: `{ rules: [["int64.in", [1, 2]]] }`,
+		},
+		{
+			name: "enum",
+			field: &data.Field{Name: "color", Type: ".test.Color", Constraints: []*data.Constraint{
+				{ID: "enum.defined_only", Value: true},
+				{ID: "enum.not_in", Value: []interface{}{int64(0)}},
+			}},
+			want: `{ zero: "UNKNOWN", rules: [["enum.defined_only", ["UNKNOWN", "RED", "BLUE"]], ["enum.not_in", ["UNKNOWN"]]] }`,
+		},
+		{
+			name: "repeated",
+			field: &data.Field{Name: "tags", Type: "string", IsRepeated: true,
+				Constraints:     []*data.Constraint{{ID: "repeated.min_items", Value: uint64(1)}},
+				ItemConstraints: []*data.Constraint{{ID: "string.max_len", Value: uint64(5)}},
+			},
+			want: `{ zero: [], repeated: true, rules: [["repeated.min_items", 1]], items: [["string.max_len", 5]] }`,
+		},
+		{
+			name:  "message",
+			field: &data.Field{Name: "msg", Type: ".test.Msg", IsRequired: true},
+			want:  `{ required: true, message: validateMsg }`,
+		},
+		{
+			name:  "map of messages",
+			field: &data.Field{Name: "by_id", Type: ".test.Msg.ByIdEntry", IsRepeated: true},
+			want:  `{ map: true, message: validateMsg }`,
+		},
+		{
+			name:  "well known type",
+			field: &data.Field{Name: "created", Type: ".google.protobuf.Timestamp", IsExternal: true},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldConstraints(r)(tt.field))
+		})
+	}
+}
+
+func TestRequiredOneOfs(t *testing.T) {
+	msg := data.NewMessage()
+	msg.OneOfFieldsNames[0] = "kind"
+	msg.OneOfFieldsNames[1] = "contact_info"
+	msg.OneOfFieldsGroups[0] = []*data.Field{{Name: "a"}, {Name: "b"}}
+	msg.OneOfFieldsGroups[1] = []*data.Field{{Name: "phone_number"}, {Name: "email"}}
+
+	r := &registry.Registry{}
+	assert.Equal(t, "", requiredOneOfs(r)(msg))
+
+	msg.RequiredOneOfs[1] = true
+	assert.Equal(t, `[["contactInfo", ["phoneNumber", "email"]]]`, requiredOneOfs(r)(msg))
+}
@@ -0,0 +1,267 @@
+
+/**
+ * Violation describes a value that breaks one of the validation rules declared
+ * in the proto with buf.validate or protoc-gen-validate.
+ */
+export interface Violation {
+  // fieldPath is the path of the offending value, e.g. items[0].name
+  fieldPath: string;
+  // constraintId identifies the rule, e.g. string.min_len
+  constraintId: string;
+  // message describes the violation
+  message: string;
+}
+
+// Constraint is a rule id along with its argument, e.g. ["string.min_len", 3].
+export type Constraint = [string, unknown];
+
+/**
+ * FieldConstraints lists the rules of a field, as generated by the validate
+ * functions.
+ */
+export interface FieldConstraints {
+  // required fails when the field is empty
+  required?: boolean;
+  // ignoreEmpty skips the rules when the field is empty
+  ignoreEmpty?: boolean;
+  // zero is the value of an absent field that isn't optional
+  zero?: unknown;
+  // repeated and map tell the shape of the field
+  repeated?: boolean;
+  map?: boolean;
+  // rules apply to the value of the field
+  rules?: Constraint[];
+  // items apply to each item of a repeated field
+  items?: Constraint[];
+  // message validates a message, or each message of a repeated or map field
+  message?: (msg: any, path: string) => Violation[];
+}
+
+/**
+ * validateMessage checks each field of a message against its constraints.
+ * requiredOneOfs lists the oneofs in which a field must be set, along with the
+ * names of their fields.
+ */
+export function validateMessage(
+  msg: unknown,
+  path: string,
+  fields: Record<string, FieldConstraints>,
+  requiredOneOfs: [string, string[]][] = []
+): Violation[] {
+  const violations: Violation[] = [];
+  if (!isPlainObject(msg)) {
+    return violations;
+  }
+  const obj = msg as Record<string, unknown>;
+  Object.keys(fields).forEach((k) =>
+    validateField(violations, path + k, obj[k], fields[k])
+  );
+  requiredOneOfs.forEach(([name, group]) => {
+    if (group.every((k) => obj[k] === undefined || obj[k] === null)) {
+      violations.push({
+        fieldPath: path + name,
+        constraintId: "required",
+        message: "exactly one field is required in oneof",
+      });
+    }
+  });
+  return violations;
+}
+
+function validateField(
+  violations: Violation[],
+  path: string,
+  value: unknown,
+  field: FieldConstraints
+): void {
+  const v = value === undefined || value === null ? field.zero : value;
+  if (isEmptyValue(v, field)) {
+    if (field.required) {
+      violations.push({
+        fieldPath: path,
+        constraintId: "required",
+        message: "value is required",
+      });
+    }
+    if (field.required || field.ignoreEmpty || v === undefined || v === null) {
+      return;
+    }
+  }
+
+  (field.rules ?? []).forEach(([id, arg]) =>
+    applyRule(violations, path, id, arg, v)
+  );
+
+  if (field.repeated && Array.isArray(v)) {
+    v.forEach((item, i) => {
+      const itemPath = `${path}[${i}]`;
+      (field.items ?? []).forEach(([id, arg]) =>
+        applyRule(violations, itemPath, id, arg, item)
+      );
+      if (field.message && isPlainObject(item)) {
+        violations.push(...field.message(item, itemPath + "."));
+      }
+    });
+  } else if (field.map && isPlainObject(v)) {
+    const entries = v as Record<string, unknown>;
+    Object.keys(entries).forEach((k) => {
+      if (field.message && isPlainObject(entries[k])) {
+        violations.push(
+          ...field.message(entries[k], `${path}[${JSON.stringify(k)}].`)
+        );
+      }
+    });
+  } else if (field.message && isPlainObject(v)) {
+    violations.push(...field.message(v, path + "."));
+  }
+}
+
+function isEmptyValue(value: unknown, field: FieldConstraints): boolean {
+  if (value === undefined || value === null) return true;
+  if (Array.isArray(value)) return value.length === 0;
+  if (field.map && isPlainObject(value)) {
+    return Object.keys(value as Record<string, unknown>).length === 0;
+  }
+  return field.zero !== undefined && String(value) === String(field.zero);
+}
+
+function applyRule(
+  violations: Violation[],
+  path: string,
+  id: string,
+  arg: unknown,
+  value: unknown
+): void {
+  const message = checkRule(id.slice(id.indexOf(".") + 1), arg, value);
+  if (message !== undefined) {
+    violations.push({ fieldPath: path, constraintId: id, message });
+  }
+}
+
+const emailPattern = /^[^@\s]+@[^@\s]+\.[^@\s]+$/;
+const hostnamePattern =
+  /^(?=.{1,253}$)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$/i;
+const uriPattern = /^[a-z][a-z0-9+.-]*:[^\s]*$/i;
+const uuidPattern =
+  /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
+
+// checkRule returns the description of the violation, or undefined if the
+// value follows the rule.
+function checkRule(
+  rule: string,
+  arg: unknown,
+  value: unknown
+): string | undefined {
+  const n = Number(value);
+  const s = String(value);
+  const length = Array.from(s).length;
+  switch (rule) {
+    case "const":
+      return equals(value, arg) ? undefined : `value must equal ${format(arg)}`;
+    case "in":
+      return (arg as unknown[]).some((a) => equals(value, a))
+        ? undefined
+        : `value must be in list ${format(arg)}`;
+    case "not_in":
+      return (arg as unknown[]).some((a) => equals(value, a))
+        ? `value must not be in list ${format(arg)}`
+        : undefined;
+    case "defined_only":
+      return (arg as unknown[]).indexOf(value) === -1
+        ? "value must be one of the defined enum values"
+        : undefined;
+    case "len":
+      return length === arg ? undefined : `value length must be ${arg} characters`;
+    case "min_len":
+      return length >= (arg as number)
+        ? undefined
+        : `value length must be at least ${arg} characters`;
+    case "max_len":
+      return length <= (arg as number)
+        ? undefined
+        : `value length must be at most ${arg} characters`;
+    case "pattern":
+      return new RegExp(arg as string).test(s)
+        ? undefined
+        : `value does not match regex pattern \`${arg}\``;
+    case "prefix":
+      return s.startsWith(arg as string)
+        ? undefined
+        : `value does not have prefix \`${arg}\``;
+    case "suffix":
+      return s.endsWith(arg as string)
+        ? undefined
+        : `value does not have suffix \`${arg}\``;
+    case "contains":
+      return s.indexOf(arg as string) !== -1
+        ? undefined
+        : `value does not contain substring \`${arg}\``;
+    case "not_contains":
+      return s.indexOf(arg as string) === -1
+        ? undefined
+        : `value contains substring \`${arg}\``;
+    case "email":
+      return emailPattern.test(s)
+        ? undefined
+        : "value must be a valid email address";
+    case "hostname":
+      return hostnamePattern.test(s)
+        ? undefined
+        : "value must be a valid hostname";
+    case "uri":
+      return uriPattern.test(s) ? undefined : "value must be a valid URI";
+    case "uuid":
+      return uuidPattern.test(s) ? undefined : "value must be a valid UUID";
+    case "lt":
+      return n < (arg as number) ? undefined : `value must be less than ${arg}`;
+    case "lte":
+      return n <= (arg as number)
+        ? undefined
+        : `value must be less than or equal to ${arg}`;
+    case "gt":
+      return n > (arg as number)
+        ? undefined
+        : `value must be greater than ${arg}`;
+    case "gte":
+      return n >= (arg as number)
+        ? undefined
+        : `value must be greater than or equal to ${arg}`;
+    case "min_items":
+      return (value as unknown[]).length >= (arg as number)
+        ? undefined
+        : `value must contain at least ${arg} item(s)`;
+    case "max_items":
+      return (value as unknown[]).length <= (arg as number)
+        ? undefined
+        : `value must contain no more than ${arg} item(s)`;
+    case "unique":
+      return new Set((value as unknown[]).map((v) => JSON.stringify(v))).size ===
+        (value as unknown[]).length
+        ? undefined
+        : "repeated value must contain unique items";
+    case "min_pairs":
+      return Object.keys(value as object).length >= (arg as number)
+        ? undefined
+        : `map must be at least ${arg} entries`;
+    case "max_pairs":
+      return Object.keys(value as object).length <= (arg as number)
+        ? undefined
+        : `map must be at most ${arg} entries`;
+    default:
+      return undefined;
+  }
+}
+
+// 64 bit integers are strings in JSON, so numbers are compared by value.
+function equals(value: unknown, arg: unknown): boolean {
+  if (typeof arg === "number") {
+    return Number(value) === arg;
+  }
+  return value === arg;
+}
+
+function format(arg: unknown): string {
+  return Array.isArray(arg)
+    ? "[" + arg.map((a) => String(a)).join(", ") + "]"
+    : String(arg);
+}
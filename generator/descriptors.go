@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// fieldDescriptor returns the fm.FieldDescriptor literal of a field.
+func fieldDescriptor(r *registry.Registry) func(field *data.Field) string {
+	fieldNameFn := fieldName(r)
+	return func(field *data.Field) string {
+		props := []string{
+			fmt.Sprintf("name: %q", field.Name),
+			fmt.Sprintf("jsonName: %q", fieldNameFn(field.Name)),
+		}
+		typeInfo, ok := r.Types[field.Type]
+		if ok && typeInfo.IsMapEntry {
+			props = append(props,
+				`kind: "map"`,
+				"repeated: false",
+				"optional: false",
+				fmt.Sprintf("mapKey: %q", typeInfo.KeyType.Type),
+				"mapValue: { "+strings.Join(typeDescriptor(r, typeInfo.ValueType.GetType()), ", ")+" }",
+			)
+			return "{ " + strings.Join(props, ", ") + " }"
+		}
+
+		typeProps := typeDescriptor(r, field.GetType())
+		props = append(props, typeProps[0])
+		props = append(props,
+			fmt.Sprintf("repeated: %t", field.IsRepeated),
+			fmt.Sprintf("optional: %t", field.IsOptional),
+		)
+		if field.IsOneOfField && !field.IsOptional {
+			props = append(props, fmt.Sprintf("oneof: %q", fieldNameFn(field.Message.OneOfFieldsNames[field.OneOfIndex])))
+		}
+		props = append(props, typeProps[1:]...)
+		return "{ " + strings.Join(props, ", ") + " }"
+	}
+}
+
+// typeDescriptor returns the properties of the fm.TypeDescriptor of a type, starting with its kind.
+func typeDescriptor(r *registry.Registry, info *data.TypeInfo) []string {
+	if !strings.HasPrefix(info.Type, ".") {
+		return []string{fmt.Sprintf("kind: %q", info.Type)}
+	}
+	kind := "message"
+	if typeInfo, ok := r.Types[info.Type]; ok && typeInfo.ProtoType == descriptorpb.FieldDescriptorProto_TYPE_ENUM {
+		kind = "enum"
+	}
+	props := []string{
+		fmt.Sprintf("kind: %q", kind),
+		fmt.Sprintf("typeName: %q", strings.TrimPrefix(info.Type, ".")),
+	}
+	if isWellKnownType(info.Type) {
+		return props
+	}
+	if module, identifier, ok := typeReference(r, info); ok {
+		props = append(props, fmt.Sprintf("%s: () => %s%sDescriptor", kind, module, identifier))
+	}
+	return props
+}
+
+// oneOfNames returns an array literal of the names of the oneofs of a message, leaving out the
+// oneofs synthesized for optional fields.
+func oneOfNames(r *registry.Registry) func(msg *data.Message) string {
+	fieldNameFn := fieldName(r)
+	return func(msg *data.Message) string {
+		indexes := make([]int, 0, len(msg.OneOfFieldsGroups))
+		for idx := range msg.OneOfFieldsGroups {
+			indexes = append(indexes, int(idx))
+		}
+		sort.Ints(indexes)
+
+		names := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			//nolint:gosec // G115: oneof indexes originate from int32 values
+			names = append(names, fmt.Sprintf("%q", fieldNameFn(msg.OneOfFieldsNames[int32(idx)])))
+		}
+		return "[" + strings.Join(names, ", ") + "]"
+	}
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFieldDescriptor(t *testing.T) {
+	msg := data.NewMessage()
+	msg.OneOfFieldsNames[0] = "choice"
+	r := &registry.Registry{
+		Types: map[string]*registry.TypeInformation{
+			".test.Msg": {
+				Package:           "test",
+				File:              "test.proto",
+				PackageIdentifier: "Msg",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+			},
+			".other.Color": {
+				Package:           "other",
+				File:              "other.proto",
+				PackageIdentifier: "Color",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+			},
+			".test.Msg.LabelsEntry": {
+				IsMapEntry: true,
+				KeyType:    &data.MapEntryType{Type: "string"},
+				ValueType:  &data.MapEntryType{Type: ".test.Msg"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		field *data.Field
+		want  string
+	}{
+		{
+			name:  "scalar",
+			field: &data.Field{Name: "display_name", Type: "string", Message: msg},
+			want:  `{ name: "display_name", jsonName: "displayName", kind: "string", repeated: false, optional: false }`,
+		},
+		{
+			name:  "optional",
+			field: &data.Field{Name: "count", Type: "int64", IsOptional: true, IsOneOfField: true, Message: msg},
+			want:  `{ name: "count", jsonName: "count", kind: "int64", repeated: false, optional: true }`,
+		},
+		{
+			name:  "repeated message",
+			field: &data.Field{Name: "items", Type: ".test.Msg", IsRepeated: true, Message: msg},
+			want: `{ name: "items", jsonName: "items", kind: "message", repeated: true, optional: false, ` +
+				`typeName: "test.Msg", message: () => MsgDescriptor }`,
+		},
+		{
+			name:  "external enum in oneof",
+			field: &data.Field{Name: "color", Type: ".other.Color", IsExternal: true, IsOneOfField: true, Message: msg},
+			want: `{ name: "color", jsonName: "color", kind: "enum", repeated: false, optional: false, oneof: "choice", ` +
+				`typeName: "other.Color", enum: () => OtherOther.ColorDescriptor }`,
+		},
+		{
+			name:  "map",
+			field: &data.Field{Name: "labels", Type: ".test.Msg.LabelsEntry", IsRepeated: true, Message: msg},
+			want: `{ name: "labels", jsonName: "labels", kind: "map", repeated: false, optional: false, mapKey: "string", ` +
+				`mapValue: { kind: "message", typeName: "test.Msg", message: () => MsgDescriptor } }`,
+		},
+		{
+			name:  "well known type",
+			field: &data.Field{Name: "at", Type: ".google.protobuf.Timestamp", IsExternal: true, Message: msg},
+			want:  `{ name: "at", jsonName: "at", kind: "message", repeated: false, optional: false, typeName: "google.protobuf.Timestamp" }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldDescriptor(r)(tt.field))
+		})
+	}
+}
+
+func TestOneOfNames(t *testing.T) {
+	msg := data.NewMessage()
+	msg.OneOfFieldsNames[0] = "_count"
+	msg.OneOfFieldsNames[1] = "payload_kind"
+	msg.OneOfFieldsGroups[1] = []*data.Field{{Name: "text"}}
+
+	r := &registry.Registry{}
+	assert.Equal(t, `["payloadKind"]`, oneOfNames(r)(msg))
+	r.UseProtoNames = true
+	assert.Equal(t, `["payload_kind"]`, oneOfNames(r)(msg))
+}
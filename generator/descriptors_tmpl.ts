@@ -0,0 +1,119 @@
+
+// ScalarKind is the proto type of a scalar value.
+export type ScalarKind =
+  | "double"
+  | "float"
+  | "int64"
+  | "uint64"
+  | "int32"
+  | "fixed64"
+  | "fixed32"
+  | "bool"
+  | "string"
+  | "bytes"
+  | "uint32"
+  | "sfixed32"
+  | "sfixed64"
+  | "sint32"
+  | "sint64";
+
+/**
+ * TypeDescriptor describes the type of a value, enums and messages are
+ * referenced lazily so that descriptors can reference each other.
+ */
+export interface TypeDescriptor {
+  kind: ScalarKind | "enum" | "message";
+  // typeName is the fully qualified name of an enum or message, e.g. pkg.Color
+  typeName?: string;
+  // enum returns the descriptor of an enum, if one was generated
+  enum?: () => EnumDescriptor;
+  // message returns the descriptor of a message, if one was generated
+  message?: () => MessageDescriptor;
+}
+
+/**
+ * FieldDescriptor describes a field of a message.
+ */
+export interface FieldDescriptor extends Omit<TypeDescriptor, "kind"> {
+  kind: TypeDescriptor["kind"] | "map";
+  // name is the name of the field in the proto
+  name: string;
+  // jsonName is the key of the field in the message
+  jsonName: string;
+  repeated: boolean;
+  optional: boolean;
+  // oneof is the name of the oneof the field belongs to
+  oneof?: string;
+  // mapKey and mapValue describe the entries of a map field
+  mapKey?: ScalarKind;
+  mapValue?: TypeDescriptor;
+}
+
+/**
+ * MessageDescriptor describes a message, as generated with the
+ * generate_descriptors option.
+ */
+export interface MessageDescriptor {
+  typeName: string;
+  fields: readonly FieldDescriptor[];
+  // oneofs lists the names of the oneofs of the message
+  oneofs: readonly string[];
+}
+
+/**
+ * EnumDescriptor describes an enum, as generated with the generate_descriptors
+ * option.
+ */
+export interface EnumDescriptor {
+  typeName: string;
+  values: readonly { name: string; number: number }[];
+}
+
+const messageDescriptors = new Map<string, MessageDescriptor>();
+const enumDescriptors = new Map<string, EnumDescriptor>();
+
+/**
+ * registerMessage adds a descriptor to the registry, generated files register
+ * the descriptors of their messages when they are loaded.
+ */
+export function registerMessage(desc: MessageDescriptor): MessageDescriptor {
+  messageDescriptors.set(desc.typeName, desc);
+  return desc;
+}
+
+/**
+ * registerEnum adds a descriptor to the registry, generated files register
+ * the descriptors of their enums when they are loaded.
+ */
+export function registerEnum(desc: EnumDescriptor): EnumDescriptor {
+  enumDescriptors.set(desc.typeName, desc);
+  return desc;
+}
+
+/**
+ * getMessageDescriptor returns the descriptor of a message given its fully
+ * qualified name, e.g. pkg.Post.
+ */
+export function getMessageDescriptor(
+  typeName: string
+): MessageDescriptor | undefined {
+  return messageDescriptors.get(typeName);
+}
+
+/**
+ * getEnumDescriptor returns the descriptor of an enum given its fully
+ * qualified name, e.g. pkg.Color.
+ */
+export function getEnumDescriptor(
+  typeName: string
+): EnumDescriptor | undefined {
+  return enumDescriptors.get(typeName);
+}
+
+/**
+ * listMessageDescriptors returns the descriptors of the messages registered so
+ * far.
+ */
+export function listMessageDescriptors(): MessageDescriptor[] {
+  return Array.from(messageDescriptors.values());
+}
@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// generateDiagnosticsReport lists the warnings one per line, the report is generated even when there
+// is no warning so that the report of a previous generation doesn't linger.
+func generateDiagnosticsReport(fileName string, warnings registry.Diagnostics) *pluginpb.CodeGeneratorResponse_File {
+	var b strings.Builder
+	for _, warning := range warnings {
+		b.WriteString(warning.String())
+		b.WriteString("\n")
+	}
+	content := b.String()
+	return &pluginpb.CodeGeneratorResponse_File{
+		Name:    &fileName,
+		Content: &content,
+	}
+}
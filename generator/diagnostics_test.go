@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+func TestGenerateDiagnosticsReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		warnings registry.Diagnostics
+		want     string
+	}{
+		{
+			name: "no warning",
+			want: "",
+		},
+		{
+			name: "located and unlocated warnings",
+			warnings: registry.Diagnostics{
+				{Severity: registry.SeverityWarning, File: "a.proto", Line: 3, Column: 5, Message: "first"},
+				{Severity: registry.SeverityWarning, File: "config.yaml", Message: "second"},
+			},
+			want: "a.proto:3:5: warning: first\nconfig.yaml: warning: second\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := generateDiagnosticsReport("diagnostics.txt", tt.warnings)
+			assert.Equal(t, "diagnostics.txt", report.GetName())
+			assert.Equal(t, tt.want, report.GetContent())
+		})
+	}
+}
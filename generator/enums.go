@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// enumEntry is a value of an enum as rendered by the templates.
+type enumEntry struct {
+	// Name is the name of the value
+	Name string
+	// Key is the key of the value in the enum or const object
+	Key string
+	// Number is the number of the value
+	Number int32
+	// Literal is the value as it appears in JSON, either its name or its number
+	Literal string
+	// Ref is the expression referencing the value in the file declaring the enum
+	Ref string
+	// IsAlias indicates the value shares its number with a previous value
+	IsAlias bool
+	// IsDuplicate indicates the value has the same literal as a previous value, which happens to
+	// aliases when enums are typed as numbers
+	IsDuplicate bool
+	// Label is the display name of the value, or its key made readable
+	Label string
+	// Description is the description of the value, if any
+	Description string
+	// IsHidden indicates the value is left out of the options of the enum
+	IsHidden bool
+}
+
+// enumEntries returns the values of an enum as rendered by the templates.
+func enumEntries(r *registry.Registry) func(enum *data.Enum) []*enumEntry {
+	return func(enum *data.Enum) []*enumEntry {
+		entries := make([]*enumEntry, 0, len(enum.Values))
+		for i, name := range enum.Values {
+			isAlias := enum.IsAlias(i)
+			entries = append(entries, &enumEntry{
+				Name:        name,
+				Key:         enum.Keys[i],
+				Number:      enum.Numbers[i],
+				Literal:     enumLiteral(r, name, enum.Numbers[i]),
+				Ref:         enumValueRef(r, enum.Name, enum.Keys[i], name, enum.Numbers[i]),
+				IsAlias:     isAlias,
+				IsDuplicate: isAlias && r.UseEnumNumbers,
+				Label:       enumLabel(enum.DisplayNames[i], enum.Keys[i]),
+				Description: enum.Descriptions[i],
+				IsHidden:    enum.Hidden[i],
+			})
+		}
+		return entries
+	}
+}
+
+// enumLabel returns the display name of an enum value, which defaults to its key in sentence case,
+// e.g. "Dark red" for DARK_RED.
+func enumLabel(displayName, key string) string {
+	if displayName != "" {
+		return displayName
+	}
+	label := strings.ReplaceAll(strings.ToLower(key), "_", " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// enumLiteral returns the JSON representation of an enum value, which follows UseEnumNumbers.
+func enumLiteral(r *registry.Registry, name string, number int32) string {
+	if r.UseEnumNumbers {
+		return strconv.Itoa(int(number))
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// enumValueRef returns the expression of an enum value, typeRef is the expression of the enum type
+// itself, e.g. Color or PkgFile.Color. Unions have no runtime object so their literals are used.
+func enumValueRef(r *registry.Registry, typeRef, key, name string, number int32) string {
+	if r.EnumStyle == registry.EnumStyleUnion {
+		return enumLiteral(r, name, number)
+	}
+	return typeRef + "." + key
+}
+
+// enumNumber returns the number of an enum value given the value names keyed by number.
+func enumNumber(values map[int32]string, name string) int32 {
+	for number, n := range values {
+		if n == name {
+			return number
+		}
+	}
+	return 0
+}
+
+// openEnumType returns the type appended to enums to accept unknown values, if OpenEnums is set.
+func openEnumType(r *registry.Registry) string {
+	if !r.OpenEnums || r.EnumStyle == registry.EnumStyleEnum {
+		return ""
+	}
+	if r.UseEnumNumbers {
+		return " | (number & {})"
+	}
+	return " | (string & {})"
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumEntries(t *testing.T) {
+	enum := data.NewEnum()
+	enum.Name = "Color"
+	enum.Values = []string{"COLOR_UNSPECIFIED", "RED", "CRIMSON"}
+	enum.Numbers = []int32{0, 1, 1}
+	enum.Keys = []string{"UNSPECIFIED", "RED", "CRIMSON"}
+	enum.DisplayNames = []string{"", "", "Crimson red"}
+	enum.Descriptions = []string{"", "The color of blood.", ""}
+	enum.Hidden = []bool{true, false, false}
+
+	tests := []struct {
+		name    string
+		options registry.Options
+		want    []*enumEntry
+	}{
+		{
+			name:    "enum",
+			options: registry.Options{EnumStyle: registry.EnumStyleEnum},
+			want: []*enumEntry{
+				{Name: "COLOR_UNSPECIFIED", Key: "UNSPECIFIED", Number: 0, Literal: `"COLOR_UNSPECIFIED"`, Ref: "Color.UNSPECIFIED", Label: "Unspecified", IsHidden: true},
+				{Name: "RED", Key: "RED", Number: 1, Literal: `"RED"`, Ref: "Color.RED", Label: "Red", Description: "The color of blood."},
+				{Name: "CRIMSON", Key: "CRIMSON", Number: 1, Literal: `"CRIMSON"`, Ref: "Color.CRIMSON", IsAlias: true, Label: "Crimson red"},
+			},
+		},
+		{
+			name:    "union",
+			options: registry.Options{EnumStyle: registry.EnumStyleUnion},
+			want: []*enumEntry{
+				{Name: "COLOR_UNSPECIFIED", Key: "UNSPECIFIED", Number: 0, Literal: `"COLOR_UNSPECIFIED"`, Ref: `"COLOR_UNSPECIFIED"`, Label: "Unspecified", IsHidden: true},
+				{Name: "RED", Key: "RED", Number: 1, Literal: `"RED"`, Ref: `"RED"`, Label: "Red", Description: "The color of blood."},
+				{Name: "CRIMSON", Key: "CRIMSON", Number: 1, Literal: `"CRIMSON"`, Ref: `"CRIMSON"`, IsAlias: true, Label: "Crimson red"},
+			},
+		},
+		{
+			name:    "const object with numbers",
+			options: registry.Options{EnumStyle: registry.EnumStyleConstObject, UseEnumNumbers: true},
+			want: []*enumEntry{
+				{Name: "COLOR_UNSPECIFIED", Key: "UNSPECIFIED", Number: 0, Literal: "0", Ref: "Color.UNSPECIFIED", Label: "Unspecified", IsHidden: true},
+				{Name: "RED", Key: "RED", Number: 1, Literal: "1", Ref: "Color.RED", Label: "Red", Description: "The color of blood."},
+				{Name: "CRIMSON", Key: "CRIMSON", Number: 1, Literal: "1", Ref: "Color.CRIMSON", IsAlias: true, IsDuplicate: true, Label: "Crimson red"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &registry.Registry{Options: tt.options}
+			assert.Equal(t, tt.want, enumEntries(r)(enum))
+		})
+	}
+}
+
+func TestOpenEnumType(t *testing.T) {
+	tests := []struct {
+		name    string
+		options registry.Options
+		want    string
+	}{
+		{name: "closed", options: registry.Options{EnumStyle: registry.EnumStyleUnion}, want: ""},
+		{name: "enum", options: registry.Options{EnumStyle: registry.EnumStyleEnum, OpenEnums: true}, want: ""},
+		{name: "union", options: registry.Options{EnumStyle: registry.EnumStyleUnion, OpenEnums: true}, want: " | (string & {})"},
+		{
+			name:    "const object with numbers",
+			options: registry.Options{EnumStyle: registry.EnumStyleConstObject, OpenEnums: true, UseEnumNumbers: true},
+			want:    " | (number & {})",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, openEnumType(&registry.Registry{Options: tt.options}))
+		})
+	}
+}
+
+func TestEnumLabel(t *testing.T) {
+	assert.Equal(t, "Crimson red", enumLabel("Crimson red", "CRIMSON"))
+	assert.Equal(t, "Dark red", enumLabel("", "DARK_RED"))
+	assert.Equal(t, "Color red", enumLabel("", "COLOR_RED"))
+}
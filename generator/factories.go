@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// fieldDefault returns the proto3 default value of a field as a typescript expression, or an empty
+// string when the field is left unset by default. Messages are null by default when EmitUnpopulated
+// is true, as the gateway emits them as null.
+func fieldDefault(r *registry.Registry) func(field *data.Field) string {
+	return func(field *data.Field) string {
+		if field.IsOptional || field.IsOneOfField {
+			return ""
+		}
+		typeInfo, ok := r.Types[field.Type]
+		if ok && typeInfo.IsMapEntry {
+			return "{}"
+		}
+		if field.IsRepeated {
+			return "[]"
+		}
+		if value := scalarDefault(field.Type); value != "" {
+			if field.TSType != "" {
+				// the zero value is cast to the type set with ts_type, e.g. a branded ID
+				return value + " as " + field.TSType
+			}
+			if typeStr := resourceNameType(r, field); typeStr != "" {
+				return value + " as " + typeStr
+			}
+			return value
+		}
+		if ok && typeInfo.EnumDefault != "" {
+			number := enumNumber(typeInfo.EnumValues, typeInfo.EnumDefault)
+			return enumValueRef(r, tsType(r, field), typeInfo.EnumDefaultKey, typeInfo.EnumDefault, number)
+		}
+		if r.EmitUnpopulated {
+			return "null"
+		}
+		return ""
+	}
+}
+
+// scalarDefault returns the proto3 default value of a scalar type, or an empty string for messages
+// and enums.
+func scalarDefault(protoType string) string {
+	switch protoType {
+	case "string":
+		return `""`
+	case "uint64", "sint64", "int64", "fixed64", "sfixed64":
+		return `"0"`
+	case "float", "double", "int32", "sint32", "uint32", "fixed32", "sfixed32":
+		return "0"
+	case "bool":
+		return "false"
+	case "bytes":
+		return "new Uint8Array()"
+	}
+	return ""
+}
+
+// defaultsType returns the type of the fields filled in by withDefaults, or an empty string if there
+// are none.
+func defaultsType(r *registry.Registry) func(msg *data.Message) string {
+	fieldDefaultFn := fieldDefault(r)
+	fieldNameFn := fieldName(r)
+	return func(msg *data.Message) string {
+		keys := make([]string, 0)
+		for _, f := range msg.Fields {
+			if value := fieldDefaultFn(f); value != "" && value != "null" {
+				keys = append(keys, fieldNameFn(f.Name)+": "+tsType(r, f))
+			}
+		}
+		if len(keys) == 0 {
+			return ""
+		}
+		return "{ " + strings.Join(keys, "; ") + " }"
+	}
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFieldDefault(t *testing.T) {
+	types := map[string]*registry.TypeInformation{
+		".test.Msg": {
+			PackageIdentifier: "Msg",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		},
+		".test.Color": {
+			PackageIdentifier: "Color",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+			EnumDefault:       "COLOR_UNSPECIFIED",
+			EnumDefaultKey:    "COLOR_UNSPECIFIED",
+		},
+		".test.Shade": {
+			PackageIdentifier: "Shade",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+			EnumDefault:       "SHADE_UNSPECIFIED",
+			EnumDefaultKey:    "UNSPECIFIED",
+		},
+		".test.Msg.LabelsEntry": {
+			IsMapEntry: true,
+			KeyType:    &data.MapEntryType{Type: "string"},
+			ValueType:  &data.MapEntryType{Type: "int32"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		emitUnpopulated bool
+		field           *data.Field
+		want            string
+	}{
+		{name: "string", field: &data.Field{Name: "a", Type: "string"}, want: `""`},
+		{name: "int64", field: &data.Field{Name: "a", Type: "int64"}, want: `"0"`},
+		{name: "double", field: &data.Field{Name: "a", Type: "double"}, want: "0"},
+		{name: "bool", field: &data.Field{Name: "a", Type: "bool"}, want: "false"},
+		{name: "bytes", field: &data.Field{Name: "a", Type: "bytes"}, want: "new Uint8Array()"},
+		{name: "enum", field: &data.Field{Name: "a", Type: ".test.Color"}, want: "Color.COLOR_UNSPECIFIED"},
+		{name: "enum with stripped prefix", field: &data.Field{Name: "a", Type: ".test.Shade"}, want: "Shade.UNSPECIFIED"},
+		{name: "repeated", field: &data.Field{Name: "a", Type: ".test.Msg", IsRepeated: true}, want: "[]"},
+		{name: "map", field: &data.Field{Name: "a", Type: ".test.Msg.LabelsEntry", IsRepeated: true}, want: "{}"},
+		{name: "message", field: &data.Field{Name: "a", Type: ".test.Msg"}, want: ""},
+		{name: "message with emit unpopulated", emitUnpopulated: true, field: &data.Field{Name: "a", Type: ".test.Msg"}, want: "null"},
+		{name: "optional", field: &data.Field{Name: "a", Type: "string", IsOptional: true}, want: ""},
+		{name: "oneof", field: &data.Field{Name: "a", Type: "string", IsOneOfField: true}, want: ""},
+		{name: "ts_type", field: &data.Field{Name: "a", Type: "string", TSType: "UserId"}, want: `"" as UserId`},
+		{name: "ts_type of int64", field: &data.Field{Name: "a", Type: "int64", TSType: "Cents"}, want: `"0" as Cents`},
+		{name: "repeated ts_type", field: &data.Field{Name: "a", Type: "string", TSType: "UserId", IsRepeated: true}, want: "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &registry.Registry{
+				Options: registry.Options{EmitUnpopulated: tt.emitUnpopulated},
+				Types:   types,
+			}
+			assert.Equal(t, tt.want, fieldDefault(r)(tt.field))
+		})
+	}
+}
+
+func TestDefaultsType(t *testing.T) {
+	msg := data.NewMessage()
+	msg.Fields = []*data.Field{
+		{Name: "display_name", Type: "string"},
+		{Name: "tags", Type: "string", IsRepeated: true},
+		{Name: "nick", Type: "string", IsOptional: true},
+	}
+
+	r := &registry.Registry{Types: map[string]*registry.TypeInformation{}}
+	assert.Equal(t, "{ displayName: string; tags: string[] }", defaultsType(r)(msg))
+	assert.Equal(t, "", defaultsType(r)(data.NewMessage()))
+}
@@ -106,8 +106,35 @@ export function b64Decode(s: any): Uint8Array {
   return new Uint8Array(buffer);
 }
 
+// Fetch is the signature of the function used to send requests, it matches the
+// global fetch function.
+export type Fetch = (
+  input: RequestInfo | URL,
+  init?: RequestInit
+) => Promise<Response>;
+
 export interface InitReq extends RequestInit {
   pathPrefix?: string;
+  // fetch overrides the transport used to send the request, defaults to the
+  // global fetch function.
+  fetch?: Fetch;
+  // validateResponse overrides whether responses are checked by the generated
+  // runtime validators, see enableResponseValidation.
+  validateResponse?: boolean;
+}
+
+// Validator throws when the value doesn't match the expected message type.
+export type Validator = (value: unknown) => void;
+
+let responseValidation = false;
+
+/**
+ * enableResponseValidation turns on checking of every response against the
+ * validators generated with the runtime_validators option. This is intended
+ * for development, to catch mismatches between the client and the gateway.
+ */
+export function enableResponseValidation(enabled = true): void {
+  responseValidation = enabled;
 }
 
 export function replacer(_key: string, value: unknown): unknown {
@@ -117,12 +144,21 @@ export function replacer(_key: string, value: unknown): unknown {
   return value;
 }
 
-export function fetchRequest<R>(path: string, init?: InitReq): Promise<R> {
-  const { pathPrefix, ...req } = init ?? {};
+export function fetchRequest<R>(
+  path: string,
+  init?: InitReq,
+  validator?: Validator
+): Promise<R> {
+  const {
+    pathPrefix,
+    fetch: transport = fetch,
+    validateResponse = responseValidation,
+    ...req
+  } = init ?? {};
 
   const url = pathPrefix ? `${pathPrefix}${path}` : path;
 
-  return fetch(url, req).then((r) =>
+  return transport(url, req).then((r) =>
     r
       .json()
       .catch((_err) => {
@@ -130,6 +166,7 @@ export function fetchRequest<R>(path: string, init?: InitReq): Promise<R> {
       })
       .then((body: R) => {
         if (!r.ok) throw body;
+        if (validator && validateResponse) validator(body);
         return body;
       })
   );
@@ -146,11 +183,17 @@ export type NotifyStreamEntityArrival<T> = (resp: T) => void;
 export async function fetchStreamingRequest<R>(
   path: string,
   callback?: NotifyStreamEntityArrival<R>,
-  init?: InitReq
+  init?: InitReq,
+  validator?: Validator
 ) {
-  const { pathPrefix, ...req } = init ?? {};
+  const {
+    pathPrefix,
+    fetch: transport = fetch,
+    validateResponse = responseValidation,
+    ...req
+  } = init ?? {};
   const url = pathPrefix ? `${pathPrefix}${path}` : path;
-  const result = await fetch(url, req);
+  const result = await transport(url, req);
   // needs to use the .ok to check the status of HTTP status code
   // http other than 200 will not throw an error, instead the .ok will become false.
   // see https://developer.mozilla.org/en-US/docs/Web/API/Fetch_API/Using_Fetch#
@@ -169,6 +212,7 @@ export async function fetchStreamingRequest<R>(
     .pipeThrough<R>(getNewLineDelimitedJSONDecodingStream<R>())
     .pipeTo(
       getNotifyEntityArrivalSink((e: R) => {
+        if (validator && validateResponse) validator(e);
         if (callback) callback(e);
       })
     );
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+const (
+	// fieldMaskType is the type of update masks.
+	fieldMaskType = ".google.protobuf.FieldMask"
+	// updateMaskField is the field of the requests holding the update mask, as recognized by the
+	// allow_patch_feature of the gateway.
+	updateMaskField = "update_mask"
+)
+
+// maskFields returns the fm.MaskFields literal of a message, which lists its fields holding a
+// message.
+func maskFields(r *registry.Registry) func(msg *data.Message) string {
+	fieldNameFn := fieldName(r)
+	return func(msg *data.Message) string {
+		entries := make([]string, 0)
+		for _, f := range msg.Fields {
+			if f.IsRepeated {
+				continue
+			}
+			if fn := fieldMaskFunction(r)(f); fn != "" {
+				entries = append(entries, fieldNameFn(f.Name)+": "+fn)
+			}
+		}
+		if len(entries) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(entries, ", ") + " }"
+	}
+}
+
+// updateMaskBody returns the field holding the body of a PATCH method whose request has an update
+// mask, or nil when the gateway doesn't derive the update mask of the method. The request must be
+// declared in the file of the service, so that the type of the body can be referenced.
+func updateMaskBody(r *registry.Registry) func(method *data.Method) *data.Field {
+	return func(method *data.Method) *data.Field {
+		if method.HTTPMethod != "PATCH" || method.HTTPRequestBody == nil || *method.HTTPRequestBody == "*" {
+			return nil
+		}
+		if method.Input.IsExternal {
+			return nil
+		}
+		typeInfo, ok := r.Types[method.Input.Type]
+		if !ok || typeInfo.Message == nil {
+			return nil
+		}
+		var body *data.Field
+		hasUpdateMask := false
+		for _, f := range typeInfo.Message.Fields {
+			if f.Name == updateMaskField && f.Type == fieldMaskType {
+				hasUpdateMask = true
+			}
+			if f.Name == *method.HTTPRequestBody {
+				body = f
+			}
+		}
+		if !hasUpdateMask || body == nil || body.IsRepeated || fieldMaskFunction(r)(body) == "" {
+			return nil
+		}
+		return body
+	}
+}
+
+// updateMaskBodyType returns the type of the body of a PATCH method, which is sent as the Input
+// variant of the message.
+func updateMaskBodyType(r *registry.Registry) func(field *data.Field) string {
+	return func(field *data.Field) string {
+		info := field.GetType()
+		info.Variant = inputVariant
+		return tsTypeInfo(r, info)
+	}
+}
+
+// fieldMaskFunction returns the fieldMaskOf function of the message held by a field.
+func fieldMaskFunction(r *registry.Registry) func(field *data.Field) string {
+	return func(field *data.Field) string {
+		return messageFunction(r, field.GetType(), "fieldMaskOf")
+	}
+}
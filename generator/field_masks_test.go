@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func updateMaskRegistry() *registry.Registry {
+	request := data.NewMessage()
+	request.Fields = []*data.Field{
+		{Name: "book", Type: ".test.Book"},
+		{Name: "update_mask", Type: ".google.protobuf.FieldMask", IsExternal: true},
+	}
+	book := data.NewMessage()
+	book.Fields = []*data.Field{
+		{Name: "title", Type: "string"},
+		{Name: "author", Type: ".test.Author"},
+		{Name: "editors", Type: ".test.Author", IsRepeated: true},
+		{Name: "labels", Type: ".test.Book.LabelsEntry", IsRepeated: true},
+		{Name: "extra", Type: ".google.protobuf.Struct", IsExternal: true},
+	}
+	return &registry.Registry{
+		Types: map[string]*registry.TypeInformation{
+			".test.UpdateBookRequest": {
+				PackageIdentifier: "UpdateBookRequest",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+				Message:           request,
+			},
+			".test.Book": {
+				PackageIdentifier: "Book",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+				Message:           book,
+			},
+			".test.Author": {
+				PackageIdentifier: "Author",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+				Message:           data.NewMessage(),
+			},
+			".test.Book.LabelsEntry": {
+				IsMapEntry: true,
+				KeyType:    &data.MapEntryType{Type: "string"},
+				ValueType:  &data.MapEntryType{Type: "string"},
+			},
+		},
+	}
+}
+
+func TestMaskFields(t *testing.T) {
+	r := updateMaskRegistry()
+	assert.Equal(t, "{ author: fieldMaskOfAuthor }", maskFields(r)(r.Types[".test.Book"].Message))
+	assert.Equal(t, "{}", maskFields(r)(r.Types[".test.Author"].Message))
+}
+
+func TestUpdateMaskBody(t *testing.T) {
+	body := func(s string) *string { return &s }
+	tests := []struct {
+		name   string
+		method *data.Method
+		want   string
+	}{
+		{
+			name: "patch",
+			method: &data.Method{
+				HTTPMethod: "PATCH", HTTPRequestBody: body("book"),
+				Input: &data.MethodArgument{Type: ".test.UpdateBookRequest"},
+			},
+			want: "book",
+		},
+		{
+			name: "put",
+			method: &data.Method{
+				HTTPMethod: "PUT", HTTPRequestBody: body("book"),
+				Input: &data.MethodArgument{Type: ".test.UpdateBookRequest"},
+			},
+		},
+		{
+			name: "whole request as body",
+			method: &data.Method{
+				HTTPMethod: "PATCH", HTTPRequestBody: body("*"),
+				Input: &data.MethodArgument{Type: ".test.UpdateBookRequest"},
+			},
+		},
+		{
+			name: "request without update mask",
+			method: &data.Method{
+				HTTPMethod: "PATCH", HTTPRequestBody: body("author"),
+				Input: &data.MethodArgument{Type: ".test.Book"},
+			},
+		},
+		{
+			name: "request from another file",
+			method: &data.Method{
+				HTTPMethod: "PATCH", HTTPRequestBody: body("book"),
+				Input: &data.MethodArgument{Type: ".test.UpdateBookRequest", IsExternal: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := updateMaskBody(updateMaskRegistry())(tt.method)
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.Name)
+			}
+		})
+	}
+}
+
+func TestFieldMaskType(t *testing.T) {
+	r := updateMaskRegistry()
+	assert.Equal(t, "string", tsType(r, &data.Field{Type: ".google.protobuf.FieldMask", IsExternal: true}))
+}
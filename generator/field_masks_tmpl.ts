@@ -0,0 +1,40 @@
+
+/**
+ * MaskFields maps the fields holding messages to the function listing the
+ * paths of their own fields, as generated by the fieldMaskOf functions.
+ */
+export type MaskFields = Record<
+  string,
+  (msg: any, prefix: string) => string[]
+>;
+
+/**
+ * fieldMaskPaths lists the paths of the fields set in a partial message, the
+ * way the gateway derives the update mask of a PATCH request from its body:
+ * messages are walked into unless they are empty, while scalars, lists, maps
+ * and null values are paths of their own.
+ */
+export function fieldMaskPaths(
+  msg: Record<string, unknown>,
+  fields: MaskFields,
+  prefix = ""
+): string[] {
+  const paths: string[] = [];
+  for (const [key, value] of Object.entries(msg)) {
+    if (value === undefined) {
+      continue;
+    }
+    const nested = fields[key];
+    if (
+      nested &&
+      typeof value === "object" &&
+      value !== null &&
+      Object.keys(value).length > 0
+    ) {
+      paths.push(...nested(value, prefix + key + "."));
+    } else {
+      paths.push(prefix + key);
+    }
+  }
+  return paths;
+}
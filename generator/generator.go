@@ -8,6 +8,7 @@ import (
 	"strings"
 	"text/template"
 
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/pluginpb"
 
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
@@ -33,6 +34,12 @@ func (t *TypeScriptGRPCGatewayGenerator) Generate(
 	resp := &pluginpb.CodeGeneratorResponse{}
 
 	filesData, err := t.Registry.Analyse(req)
+	var diagnostics registry.Diagnostics
+	if errors.As(err, &diagnostics) {
+		// problems of the input files are reported to protoc, which prints them and fails
+		resp.Error = proto.String(diagnostics.Error())
+		return resp, nil
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "error analysing proto files")
 	}
@@ -49,16 +56,30 @@ func (t *TypeScriptGRPCGatewayGenerator) Generate(
 
 		slog.Debug("generating file", slog.String("fileName", fileData.TSFileName))
 		data := &TemplateData{
-			File:               fileData,
-			EnableStylingCheck: t.Registry.EnableStylingCheck,
-			UseStaticClasses:   t.Registry.UseStaticClasses,
+			File:                         fileData,
+			EnableStylingCheck:           t.Registry.EnableStylingCheck,
+			UseStaticClasses:             t.Registry.UseStaticClasses,
+			GenerateFakeServers:          t.Registry.GenerateFakeServers,
+			RuntimeValidators:            t.Registry.RuntimeValidators,
+			GenerateConstraintValidators: t.Registry.GenerateConstraintValidators,
+			GenerateFactories:            t.Registry.GenerateFactories,
+			GenerateTaggedOneOfs:         t.Registry.GenerateTaggedOneOfs,
+			GenerateEnumHelpers:          t.Registry.GenerateEnumHelpers,
+			GenerateEnumLabels:           t.Registry.GenerateEnumLabels,
+			GenerateDescriptors:          t.Registry.GenerateDescriptors,
+			GenerateMessageUtils:         t.Registry.GenerateMessageUtils,
+			GenerateUpdateMasks:          t.Registry.GenerateUpdateMasks,
+			GeneratePageIterators:        t.Registry.GeneratePageIterators,
+			GenerateOperationHelpers:     t.Registry.GenerateOperationHelpers,
+			GenerateResourceNames:        t.Registry.GenerateResourceNames,
+			StreamingIterators:           t.Registry.UsesStreamingIterators(),
 		}
 		generated, err := t.generateFile(data, tmpl)
 		if err != nil {
 			return nil, errors.Wrap(err, "error generating file")
 		}
 		resp.File = append(resp.File, generated)
-		requiresFetchModule = requiresFetchModule || fileData.Services.RequiresFetchModule()
+		requiresFetchModule = requiresFetchModule || t.Registry.RequiresFetchModule(fileData)
 	}
 
 	if requiresFetchModule {
@@ -72,6 +93,10 @@ func (t *TypeScriptGRPCGatewayGenerator) Generate(
 		resp.File = append(resp.File, generatedFetch)
 	}
 
+	if t.Registry.DiagnosticsReport != "" {
+		resp.File = append(resp.File, generateDiagnosticsReport(t.Registry.DiagnosticsReport, t.Registry.Warnings()))
+	}
+
 	return resp, nil
 }
 
@@ -103,8 +128,18 @@ func (t *TypeScriptGRPCGatewayGenerator) generateFetchModule(
 	w := bytes.NewBufferString("")
 	fileName := filepath.Join(t.Registry.FetchModuleDirectory, t.Registry.FetchModuleFilename)
 	err := tmpl.Execute(w, &TemplateData{
-		EnableStylingCheck: t.Registry.EnableStylingCheck,
-		UseStaticClasses:   t.Registry.UseStaticClasses,
+		EnableStylingCheck:           t.Registry.EnableStylingCheck,
+		UseStaticClasses:             t.Registry.UseStaticClasses,
+		GenerateFakeServers:          t.Registry.GenerateFakeServers,
+		RuntimeValidators:            t.Registry.RuntimeValidators,
+		GenerateConstraintValidators: t.Registry.GenerateConstraintValidators,
+		GenerateDescriptors:          t.Registry.GenerateDescriptors,
+		GenerateMessageUtils:         t.Registry.GenerateMessageUtils,
+		GenerateUpdateMasks:          t.Registry.GenerateUpdateMasks,
+		GeneratePageIterators:        t.Registry.GeneratePageIterators,
+		GenerateOperationHelpers:     t.Registry.GenerateOperationHelpers,
+		GenerateResourceNames:        t.Registry.GenerateResourceNames,
+		StreamingIterators:           t.Registry.UsesStreamingIterators(),
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "error generating fetch module at %s", fileName)
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// fieldSemantics returns the fm.FieldSemantics literal of a field, or an empty string when the
+// field is compared, copied and merged as a plain value.
+func fieldSemantics(r *registry.Registry) func(field *data.Field) string {
+	fieldDefaultFn := fieldDefault(r)
+	fieldNameFn := fieldName(r)
+	return func(field *data.Field) string {
+		typeInfo, ok := r.Types[field.Type]
+		isMap := ok && typeInfo.IsMapEntry
+
+		info := field.GetType()
+		if isMap {
+			info = typeInfo.ValueType.GetType()
+		}
+
+		props := make([]string, 0)
+		if zero := fieldDefaultFn(field); zero != "" && zero != "null" {
+			props = append(props, "zero: "+zero)
+		}
+		if field.IsOneOfField && !field.IsOptional {
+			props = append(props, fmt.Sprintf("oneof: %q", fieldNameFn(field.Message.OneOfFieldsNames[field.OneOfIndex])))
+		}
+		if isMap {
+			props = append(props, "map: true")
+		} else if field.IsRepeated {
+			props = append(props, "repeated: true")
+		}
+		// repeated fields are concatenated and map entries replaced, their messages are never merged
+		fns := []string{"equals", "clone", "merge"}
+		if isMap || field.IsRepeated {
+			fns = fns[:2]
+		}
+		for _, fn := range fns {
+			if ref := messageFunction(r, info, fn); ref != "" {
+				props = append(props, fn+": "+ref)
+			}
+		}
+		if len(props) == 0 {
+			return ""
+		}
+		return "{ " + strings.Join(props, ", ") + " }"
+	}
+}
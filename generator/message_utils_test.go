@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFieldSemantics(t *testing.T) {
+	msg := data.NewMessage()
+	msg.OneOfFieldsNames[0] = "body"
+	types := map[string]*registry.TypeInformation{
+		".test.Msg": {
+			PackageIdentifier: "Msg",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		},
+		".test.Msg.ChildrenEntry": {
+			IsMapEntry: true,
+			KeyType:    &data.MapEntryType{Type: "string"},
+			ValueType:  &data.MapEntryType{Type: ".test.Msg"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		emitUnpopulated bool
+		field           *data.Field
+		want            string
+	}{
+		{name: "scalar", field: &data.Field{Name: "title", Type: "string", Message: msg}, want: `{ zero: "" }`},
+		{name: "optional", field: &data.Field{Name: "rank", Type: "int32", IsOptional: true, IsOneOfField: true, Message: msg}, want: ""},
+		{name: "oneof", field: &data.Field{Name: "text", Type: "string", IsOneOfField: true, Message: msg}, want: `{ oneof: "body" }`},
+		{
+			name:  "message",
+			field: &data.Field{Name: "parent", Type: ".test.Msg", Message: msg},
+			want:  "{ equals: equalsMsg, clone: cloneMsg, merge: mergeMsg }",
+		},
+		{
+			name:            "message with emit unpopulated",
+			emitUnpopulated: true,
+			field:           &data.Field{Name: "parent", Type: ".test.Msg", Message: msg},
+			want:            "{ equals: equalsMsg, clone: cloneMsg, merge: mergeMsg }",
+		},
+		{
+			name:  "repeated message",
+			field: &data.Field{Name: "items", Type: ".test.Msg", IsRepeated: true, Message: msg},
+			want:  "{ zero: [], repeated: true, equals: equalsMsg, clone: cloneMsg }",
+		},
+		{
+			name:  "map of messages",
+			field: &data.Field{Name: "children", Type: ".test.Msg.ChildrenEntry", IsRepeated: true, Message: msg},
+			want:  "{ zero: {}, map: true, equals: equalsMsg, clone: cloneMsg }",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &registry.Registry{
+				Options: registry.Options{EmitUnpopulated: tt.emitUnpopulated},
+				Types:   types,
+			}
+			assert.Equal(t, tt.want, fieldSemantics(r)(tt.field))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+
+/**
+ * FieldSemantics describes how a field is compared, copied and merged, as
+ * generated by the equals, clone and merge functions of messages.
+ */
+export interface FieldSemantics {
+  // zero is the default value of a field that doesn't track presence, an
+  // absent field and a field holding its zero value are the same
+  zero?: unknown;
+  // oneof is the name of the oneof the field belongs to
+  oneof?: string;
+  // repeated and map tell the shape of the field
+  repeated?: boolean;
+  map?: boolean;
+  // equals, clone and merge handle a message, or each message of a repeated
+  // or map field
+  equals?: (a: any, b: any) => boolean;
+  clone?: (msg: any) => any;
+  merge?: (target: any, source: any) => any;
+}
+
+type Fields = Record<string, FieldSemantics>;
+
+/**
+ * equalsMessage compares two messages field by field, absent fields are equal
+ * to fields holding their zero value unless the field tracks presence.
+ */
+export function equalsMessage(a: any, b: any, fields: Fields): boolean {
+  if (a === b) {
+    return true;
+  }
+  if (isAbsent(a) || isAbsent(b)) {
+    return false;
+  }
+  // an array rather than a Set, which can't be iterated when compiling to ES5
+  const keys = Object.keys(a).concat(Object.keys(b).filter((key) => !(key in a)));
+  for (const key of keys) {
+    const field = fields[key] ?? {};
+    const aSet = isSet(a[key], field);
+    const bSet = isSet(b[key], field);
+    if (aSet !== bSet) {
+      return false;
+    }
+    if (aSet && !fieldEquals(a[key], b[key], field)) {
+      return false;
+    }
+  }
+  return true;
+}
+
+/**
+ * cloneMessage returns a deep copy of a message, bytes included.
+ */
+export function cloneMessage<T>(msg: T, fields: Fields): T {
+  if (isAbsent(msg)) {
+    return msg;
+  }
+  const copy: any = {};
+  for (const [key, value] of Object.entries(msg as any)) {
+    copy[key] = cloneField(value, fields[key] ?? {});
+  }
+  return copy;
+}
+
+/**
+ * mergeMessage returns a copy of target in which the fields set in source are
+ * merged: scalars are replaced, messages are merged, repeated fields are
+ * concatenated, map entries are replaced by key and setting a field of a oneof
+ * clears the other fields of the oneof.
+ */
+export function mergeMessage<T>(target: T, source: T, fields: Fields): T {
+  const result: any = cloneMessage(target ?? ({} as T), fields);
+  if (isAbsent(source)) {
+    return result;
+  }
+  for (const [key, value] of Object.entries(source as any)) {
+    const field = fields[key] ?? {};
+    if (!isSet(value, field)) {
+      continue;
+    }
+    if (field.oneof !== undefined) {
+      for (const [other, otherField] of Object.entries(fields)) {
+        if (other !== key && otherField.oneof === field.oneof) {
+          delete result[other];
+        }
+      }
+    }
+    const current = result[key];
+    if (field.repeated && Array.isArray(current)) {
+      result[key] = [...current, ...(cloneField(value, field) as unknown[])];
+    } else if (field.map && !isAbsent(current)) {
+      result[key] = { ...current, ...(cloneField(value, field) as object) };
+    } else if (field.merge && !field.repeated && !field.map && !isAbsent(current)) {
+      result[key] = field.merge(current, value);
+    } else {
+      result[key] = cloneField(value, field);
+    }
+  }
+  return result;
+}
+
+function isAbsent(value: unknown): value is null | undefined {
+  return value === undefined || value === null;
+}
+
+// isSet tells whether a field is set, fields holding their zero value are not
+// unless they track presence.
+function isSet(value: unknown, field: FieldSemantics): boolean {
+  if (isAbsent(value)) {
+    return false;
+  }
+  if (field.zero === undefined) {
+    return true;
+  }
+  return !valueEquals(value, field.zero);
+}
+
+function fieldEquals(a: any, b: any, field: FieldSemantics): boolean {
+  const itemEquals = field.equals ?? valueEquals;
+  if (field.repeated) {
+    return (
+      Array.isArray(a) &&
+      Array.isArray(b) &&
+      a.length === b.length &&
+      a.every((v, i) => itemEquals(v, b[i]))
+    );
+  }
+  if (field.map) {
+    const aKeys = Object.keys(a);
+    return (
+      aKeys.length === Object.keys(b).length &&
+      aKeys.every((k) => k in b && itemEquals(a[k], b[k]))
+    );
+  }
+  return itemEquals(a, b);
+}
+
+function cloneField(value: any, field: FieldSemantics): unknown {
+  const cloneItem = field.clone ?? cloneValue;
+  if (isAbsent(value)) {
+    return value;
+  }
+  if (field.repeated) {
+    return (value as unknown[]).map((v) => cloneItem(v));
+  }
+  if (field.map) {
+    const copy: Record<string, unknown> = {};
+    for (const [k, v] of Object.entries(value)) {
+      copy[k] = cloneItem(v);
+    }
+    return copy;
+  }
+  return cloneItem(value);
+}
+
+// valueEquals compares scalars, bytes and the JSON form of well known types.
+function valueEquals(a: unknown, b: unknown): boolean {
+  if (a === b) {
+    return true;
+  }
+  if (a instanceof Uint8Array || b instanceof Uint8Array) {
+    return bytesEquals(a, b);
+  }
+  if (Array.isArray(a) && Array.isArray(b)) {
+    return a.length === b.length && a.every((v, i) => valueEquals(v, b[i]));
+  }
+  if (
+    typeof a === "object" &&
+    typeof b === "object" &&
+    a !== null &&
+    b !== null &&
+    !Array.isArray(a) &&
+    !Array.isArray(b)
+  ) {
+    const aKeys = Object.keys(a);
+    return (
+      aKeys.length === Object.keys(b).length &&
+      aKeys.every((k) => k in b && valueEquals((a as any)[k], (b as any)[k]))
+    );
+  }
+  return false;
+}
+
+// bytes are strings in JSON and Uint8Arrays once decoded, either way empty
+// bytes are equal.
+function bytesEquals(a: unknown, b: unknown): boolean {
+  const aLength = (a as Uint8Array | string).length;
+  const bLength = (b as Uint8Array | string).length;
+  if (!(a instanceof Uint8Array) || !(b instanceof Uint8Array)) {
+    return aLength === 0 && bLength === 0;
+  }
+  return aLength === bLength && a.every((v, i) => v === b[i]);
+}
+
+function cloneValue(value: unknown): unknown {
+  if (value instanceof Uint8Array) {
+    return value.slice();
+  }
+  if (Array.isArray(value)) {
+    return value.map(cloneValue);
+  }
+  if (typeof value === "object" && value !== null) {
+    const copy: Record<string, unknown> = {};
+    for (const [k, v] of Object.entries(value)) {
+      copy[k] = cloneValue(v);
+    }
+    return copy;
+  }
+  return value;
+}
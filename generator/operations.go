@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// operationType returns the fm.Operation type of a method output annotated with
+// google.longrunning.operation_info, types which can't be resolved are left unknown.
+func operationType(r *registry.Registry, op *data.Operation) string {
+	return "fm.Operation<" + operationArgumentType(r, op.ResponseType) + ", " +
+		operationArgumentType(r, op.MetadataType) + ">"
+}
+
+// operationResponseType returns the type a long-running operation resolves to once it is done.
+func operationResponseType(r *registry.Registry) func(op *data.Operation) string {
+	return func(op *data.Operation) string {
+		return operationArgumentType(r, op.ResponseType)
+	}
+}
+
+// operationResponseValidator returns the assert function for the response of a long-running
+// operation, or an empty string if no validator is generated for the type.
+func operationResponseValidator(r *registry.Registry) func(op *data.Operation) string {
+	validator := responseValidator(r)
+	return func(op *data.Operation) string {
+		if op.ResponseType == nil {
+			return ""
+		}
+		return validator(op.ResponseType)
+	}
+}
+
+func operationArgumentType(r *registry.Registry, arg *data.MethodArgument) string {
+	if arg == nil {
+		return "unknown"
+	}
+	info := arg.GetType()
+	info.Variant = outputVariant
+	return tsTypeInfo(r, info)
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestOperationTypes(t *testing.T) {
+	r := &registry.Registry{
+		Options: registry.Options{RuntimeValidators: registry.RuntimeValidatorsStandalone},
+		Types: map[string]*registry.TypeInformation{
+			".test.Book": {
+				PackageIdentifier: "Book",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+				Message:           data.NewMessage(),
+			},
+			".google.longrunning.Operation": {
+				PackageIdentifier: "Operation",
+				ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+				Message:           data.NewMessage(),
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		operation *data.Operation
+		output    string
+		response  string
+		validator string
+	}{
+		{
+			name:      "typed",
+			operation: &data.Operation{ResponseType: &data.MethodArgument{Type: ".test.Book"}},
+			output:    "fm.Operation<Book, unknown>",
+			response:  "Book",
+			validator: "assertBook",
+		},
+		{
+			name:      "unresolved",
+			operation: &data.Operation{},
+			output:    "fm.Operation<unknown, unknown>",
+			response:  "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arg := &data.MethodArgument{Type: ".google.longrunning.Operation", Operation: tt.operation}
+			assert.Equal(t, tt.output, tsVariantType(r, outputVariant)(arg))
+			assert.Equal(t, tt.response, operationResponseType(r)(tt.operation))
+			assert.Equal(t, tt.validator, operationResponseValidator(r)(tt.operation))
+			assert.Equal(t, "", responseValidator(r)(arg))
+		})
+	}
+}
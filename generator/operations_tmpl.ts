@@ -0,0 +1,91 @@
+
+// OperationError is the status of a long-running operation which failed.
+export interface OperationError {
+  code?: number;
+  message?: string;
+  details?: unknown[];
+}
+
+// Operation is a google.longrunning.Operation whose response and metadata are
+// typed with the types declared by google.longrunning.operation_info.
+export interface Operation<R, M> {
+  name?: string;
+  metadata?: M;
+  done?: boolean;
+  error?: OperationError;
+  response?: R;
+}
+
+export interface WaitOptions {
+  // pollInterval is the delay in milliseconds between two polls, defaults to
+  // one second.
+  pollInterval?: number;
+  // signal stops polling with an AbortError once it is aborted.
+  signal?: AbortSignal | null;
+  // initReq is used for the requests getting the state of the operation.
+  initReq?: InitReq;
+  // path returns the path of the GetOperation binding of the Operations
+  // service for an operation name, defaults to /v1/{name}.
+  path?: (name: string) => string;
+}
+
+/**
+ * waitForOperation polls the Operations service until the operation is done,
+ * then resolves with its response with the type URL of the Any removed. It
+ * rejects with the error of the operation if it failed. The operation is
+ * fetched from /v1/{name} unless options.path maps its name to another path.
+ */
+export async function waitForOperation<R, M>(
+  op: Operation<R, M>,
+  options?: WaitOptions,
+  validator?: Validator
+): Promise<R> {
+  const {
+    pollInterval = 1000,
+    signal = options?.initReq?.signal,
+    initReq,
+    path = (name: string) => `/v1/${name}`,
+  } = options ?? {};
+  while (!op.done) {
+    if (!op.name) {
+      throw new Error("operation has no name to poll");
+    }
+    await sleep(pollInterval, signal);
+    op = await fetchRequest<Operation<R, M>>(path(op.name), {
+      ...initReq,
+      signal,
+      method: "GET",
+    });
+  }
+  if (op.error) {
+    throw op.error;
+  }
+  const { "@type": _typeURL, ...response } = (op.response ?? {}) as Record<
+    string,
+    unknown
+  >;
+  if (validator && (initReq?.validateResponse ?? responseValidation)) {
+    validator(response);
+  }
+  return response as R;
+}
+
+function sleep(ms: number, signal?: AbortSignal | null): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const aborted = () =>
+      signal?.reason ?? new DOMException("The operation was aborted.", "AbortError");
+    if (signal?.aborted) {
+      reject(aborted());
+      return;
+    }
+    const onAbort = () => {
+      clearTimeout(timer);
+      reject(aborted());
+    };
+    const timer = setTimeout(() => {
+      signal?.removeEventListener("abort", onAbort);
+      resolve();
+    }, ms);
+    signal?.addEventListener("abort", onAbort, { once: true });
+  });
+}
@@ -0,0 +1,41 @@
+
+/**
+ * fetchPages fetches the pages of a List method following AIP-158, starting
+ * from the page token of the request and until the response has no next page
+ * token. It stops with an AbortError once the signal is aborted.
+ */
+export async function* fetchPages<Req, Res>(
+  req: Req,
+  fetchPage: (req: Req) => Promise<Res>,
+  pageTokenKey: keyof Req,
+  nextPageTokenKey: keyof Res,
+  signal?: AbortSignal | null
+): AsyncGenerator<Res, void, undefined> {
+  let pageToken = req[pageTokenKey] as unknown;
+  do {
+    throwIfAborted(signal);
+    const page = await fetchPage({ ...req, [pageTokenKey]: pageToken });
+    throwIfAborted(signal);
+    yield page;
+    pageToken = page[nextPageTokenKey];
+  } while (pageToken);
+}
+
+/**
+ * fetchItems yields the items of each page fetched by fetchPages.
+ */
+export async function* fetchItems<Res, K extends keyof Res>(
+  pages: AsyncIterable<Res>,
+  itemsKey: K
+): AsyncGenerator<NonNullable<Res[K]> extends (infer T)[] ? T : never, void, undefined> {
+  for await (const page of pages) {
+    const items = (page[itemsKey] ?? []) as unknown as any[];
+    yield* items;
+  }
+}
+
+function throwIfAborted(signal?: AbortSignal | null): void {
+  if (signal?.aborted) {
+    throw signal.reason ?? new DOMException("The operation was aborted.", "AbortError");
+  }
+}
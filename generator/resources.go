@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// resourceVariable matches the variables of resource name patterns, e.g. {book}.
+var resourceVariable = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+// resourceNameType returns the branded type of the names of the resource referenced by a field, or
+// an empty string when the field doesn't reference a known resource.
+func resourceNameType(r *registry.Registry, field *data.Field) string {
+	if !r.GenerateResourceNames || field.Resource == nil {
+		return ""
+	}
+	info, ok := r.Resources[field.Resource.Type]
+	if !ok {
+		return ""
+	}
+	typeStr := info.Resource.Name
+	if field.Resource.IsExternal {
+		typeStr = r.ModuleIdentifier(info.Package, info.File) + "." + typeStr
+	}
+	if field.IsRepeated {
+		typeStr += "[]"
+	}
+	return typeStr
+}
+
+// resourceParts returns the type of the variables of the patterns of a resource, which is a union
+// when the patterns have different variables.
+func resourceParts(resource *data.Resource) string {
+	types := make([]string, 0, len(resource.Patterns))
+	seen := make(map[string]bool)
+	for _, pattern := range resource.Patterns {
+		keys := make([]string, 0)
+		for _, match := range resourceVariable.FindAllStringSubmatch(pattern, -1) {
+			keys = append(keys, match[1]+": string")
+		}
+		t := "{}"
+		if len(keys) > 0 {
+			t = "{ " + strings.Join(keys, "; ") + " }"
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return strings.Join(types, " | ")
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceParts(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		expected string
+	}{
+		{
+			name:     "single pattern",
+			patterns: []string{"projects/{project}/books/{book}"},
+			expected: "{ project: string; book: string }",
+		},
+		{
+			name:     "multiple patterns",
+			patterns: []string{"projects/{project}/books/{book}", "publishers/{publisher}/books/{book}"},
+			expected: "{ project: string; book: string } | { publisher: string; book: string }",
+		},
+		{
+			name:     "same variables",
+			patterns: []string{"users/{user}", "people/{user}"},
+			expected: "{ user: string }",
+		},
+		{
+			name:     "singleton",
+			patterns: []string{"config"},
+			expected: "{}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resourceParts(&data.Resource{Patterns: tt.patterns}))
+		})
+	}
+}
+
+func TestResourceNameType(t *testing.T) {
+	r := &registry.Registry{
+		Options: registry.Options{GenerateResourceNames: true},
+		Resources: map[string]*registry.ResourceInformation{
+			"library.example.com/Book": {
+				Package:  "library",
+				File:     "library/book.proto",
+				Resource: &data.Resource{Type: "library.example.com/Book", Name: "BookName"},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		field    *data.Field
+		expected string
+	}{
+		{
+			name:     "local",
+			field:    &data.Field{Type: "string", Resource: &data.ResourceReference{Type: "library.example.com/Book"}},
+			expected: "BookName",
+		},
+		{
+			name: "external and repeated",
+			field: &data.Field{Type: "string", IsRepeated: true,
+				Resource: &data.ResourceReference{Type: "library.example.com/Book", IsExternal: true}},
+			expected: "LibraryBook.BookName[]",
+		},
+		{
+			name:     "unknown resource",
+			field:    &data.Field{Type: "string", Resource: &data.ResourceReference{Type: "library.example.com/Shelf"}},
+			expected: "string",
+		},
+		{
+			name:     "no reference",
+			field:    &data.Field{Type: "string"},
+			expected: "string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tsType(r, tt.field))
+		})
+	}
+}
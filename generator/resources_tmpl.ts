@@ -0,0 +1,89 @@
+
+// ResourceNameParts are the values of the variables of a resource name
+// pattern, keyed by the names of the variables.
+export type ResourceNameParts = Record<string, string>;
+
+const resourcePatterns = new Map<
+  string,
+  { regexp: RegExp; variables: string[] }
+>();
+
+function resourcePattern(pattern: string): { regexp: RegExp; variables: string[] } {
+  let compiled = resourcePatterns.get(pattern);
+  if (!compiled) {
+    const variables: string[] = [];
+    const source = pattern
+      .split(/(\{[^}]+\})/)
+      .map((part) => {
+        if (part.startsWith("{") && part.endsWith("}")) {
+          variables.push(part.slice(1, -1).split("=")[0]);
+          return "([^/]+)";
+        }
+        return part.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+      })
+      .join("");
+    compiled = { regexp: new RegExp(`^${source}$`), variables };
+    resourcePatterns.set(pattern, compiled);
+  }
+  return compiled;
+}
+
+/**
+ * formatResourceName builds a resource name with the first pattern whose
+ * variables are all given. It throws when no pattern matches or when a value
+ * is empty or contains a slash.
+ */
+export function formatResourceName(
+  patterns: readonly string[],
+  parts: ResourceNameParts
+): string {
+  for (const pattern of patterns) {
+    const { variables } = resourcePattern(pattern);
+    if (!variables.every((v) => typeof parts[v] === "string")) {
+      continue;
+    }
+    return pattern.replace(/\{([^}=]+)(=[^}]*)?\}/g, (_match, v: string) => {
+      const value = parts[v];
+      if (value === "" || value.includes("/")) {
+        throw new Error(`invalid value ${JSON.stringify(value)} for ${v} in ${pattern}`);
+      }
+      return value;
+    });
+  }
+  throw new Error(`no resource name pattern matches ${JSON.stringify(Object.keys(parts))}, expected one of ${patterns.join(", ")}`);
+}
+
+/**
+ * matchResourceName returns the variables of a resource name matching one of
+ * the patterns, or undefined if it matches none.
+ */
+export function matchResourceName(
+  patterns: readonly string[],
+  name: string
+): ResourceNameParts | undefined {
+  for (const pattern of patterns) {
+    const { regexp, variables } = resourcePattern(pattern);
+    const match = regexp.exec(name);
+    if (match) {
+      const parts: ResourceNameParts = {};
+      variables.forEach((v, i) => (parts[v] = match[i + 1]));
+      return parts;
+    }
+  }
+  return undefined;
+}
+
+/**
+ * parseResourceName returns the variables of a resource name, it throws when
+ * the name matches none of the patterns.
+ */
+export function parseResourceName(
+  patterns: readonly string[],
+  name: string
+): ResourceNameParts {
+  const parts = matchResourceName(patterns, name);
+  if (!parts) {
+    throw new Error(`${JSON.stringify(name)} matches none of ${patterns.join(", ")}`);
+  }
+  return parts;
+}
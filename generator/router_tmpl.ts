@@ -0,0 +1,262 @@
+
+/**
+ * In-memory router used by the generated fake servers. It implements the Fetch
+ * signature so it can be passed as `fetch` in InitReq, which lets the generated
+ * client talk to a fake implementation of a service without any network.
+ */
+
+// FieldKind tells the router how to decode path and query parameters, fields
+// that aren't listed are decoded as strings.
+export type FieldKind =
+  | "string"
+  | "number"
+  | "boolean"
+  | "string[]"
+  | "number[]"
+  | "boolean[]";
+
+/**
+ * Route describes a single HTTP binding of a service method.
+ */
+export interface Route {
+  // method is the HTTP verb of the binding
+  method: string;
+  // path is the path template of the binding, e.g. /v1/{name=books/*}
+  path: string;
+  // body is the request field mapped to the HTTP body, "*" for the whole
+  // request and "" for none
+  body: string;
+  // fields lists the non string fields that may be set from the URL
+  fields: Record<string, FieldKind>;
+  // streaming indicates the handler is a server streaming method
+  streaming: boolean;
+  // handler is the implementation of the method, routes without a handler
+  // respond with an unimplemented error
+  handler?: (req: any, entityNotifier: NotifyStreamEntityArrival<any>) => any;
+}
+
+interface CompiledRoute {
+  route: Route;
+  regexp: RegExp;
+  params: string[];
+}
+
+interface RoutedRequest {
+  method: string;
+  url: URL;
+  body: string;
+}
+
+/**
+ * createRouter returns a Fetch function that dispatches requests to the
+ * handler of the first matching route, following the grpc-gateway conventions
+ * for path parameters, query parameters and request bodies.
+ */
+export function createRouter(routes: Route[]): Fetch {
+  const compiled = routes.map((route): CompiledRoute => {
+    const { regexp, params } = compilePathTemplate(route.path);
+    return { route, regexp, params };
+  });
+
+  return async (input, init) => {
+    const request = await readRequest(input, init);
+    for (const { route, regexp, params } of compiled) {
+      if (route.method !== request.method) {
+        continue;
+      }
+      const match = regexp.exec(request.url.pathname);
+      if (!match) {
+        continue;
+      }
+      if (!route.handler) {
+        return errorResponse(501, 12, "method not implemented");
+      }
+
+      let req: Record<string, unknown>;
+      try {
+        req = decodeRequest(route, params, match, request);
+      } catch (err) {
+        return errorResponse(400, 3, errorMessage(err));
+      }
+
+      try {
+        if (route.streaming) {
+          let lines = "";
+          await route.handler(req, (resp: unknown) => {
+            lines += JSON.stringify({ result: resp }, replacer) + "\n";
+          });
+          return new Response(lines, { status: 200 });
+        }
+        const resp: unknown = await route.handler(req, () => undefined);
+        return jsonResponse(200, resp ?? {});
+      } catch (err) {
+        return exceptionResponse(err);
+      }
+    }
+    return errorResponse(404, 5, "Not Found");
+  };
+}
+
+/**
+ * Turns a path template into a regular expression, each variable becomes a
+ * capture group and its name is returned in params.
+ */
+function compilePathTemplate(template: string): {
+  regexp: RegExp;
+  params: string[];
+} {
+  const params: string[] = [];
+  const variable = /{([^=}]+)(?:=([^}]+))?}/g;
+  let source = "";
+  let last = 0;
+  let m: RegExpExecArray | null;
+  while ((m = variable.exec(template)) !== null) {
+    source += escapeRegExp(template.slice(last, m.index));
+    params.push(m[1]);
+    const segments = (m[2] ?? "*").split("/").map((segment) => {
+      if (segment === "**") return ".+";
+      if (segment === "*") return "[^/]+";
+      return escapeRegExp(segment);
+    });
+    source += "(" + segments.join("/") + ")";
+    last = variable.lastIndex;
+  }
+  source += escapeRegExp(template.slice(last));
+  return { regexp: new RegExp("^" + source + "$"), params };
+}
+
+function escapeRegExp(s: string): string {
+  return s.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+}
+
+async function readRequest(
+  input: RequestInfo | URL,
+  init?: RequestInit
+): Promise<RoutedRequest> {
+  let url: string;
+  let method = init?.method;
+  let body = typeof init?.body === "string" ? init.body : "";
+  if (typeof input === "string") {
+    url = input;
+  } else if (input instanceof URL) {
+    url = input.toString();
+  } else {
+    url = input.url;
+    method = method ?? input.method;
+    if (!init?.body) {
+      body = await input.text();
+    }
+  }
+  return {
+    method: (method ?? "GET").toUpperCase(),
+    url: new URL(url, "http://localhost"),
+    body,
+  };
+}
+
+/**
+ * Builds the request message out of the body, the path parameters and the
+ * query parameters, in that order of precedence.
+ */
+function decodeRequest(
+  route: Route,
+  params: string[],
+  match: RegExpExecArray,
+  request: RoutedRequest
+): Record<string, unknown> {
+  const req: Record<string, unknown> = {};
+
+  if (request.body !== "" && route.body !== "") {
+    const body: unknown = JSON.parse(request.body);
+    if (route.body === "*") {
+      Object.assign(req, body);
+    } else {
+      req[route.body] = body;
+    }
+  }
+
+  params.forEach((param, i) => {
+    setField(
+      req,
+      param,
+      decodeValue(route.fields[param], decodeURIComponent(match[i + 1]))
+    );
+  });
+
+  if (route.body === "*") {
+    return req;
+  }
+
+  const query: Record<string, string[]> = {};
+  request.url.searchParams.forEach((value, key) => {
+    (query[key] ?? (query[key] = [])).push(value);
+  });
+  Object.keys(query).forEach((key) => {
+    if (params.indexOf(key) !== -1) {
+      return;
+    }
+    const kind = route.fields[key];
+    const values = query[key].map((v) => decodeValue(kind, v));
+    const repeated = kind ? kind.slice(-2) === "[]" : values.length > 1;
+    setField(req, key, repeated ? values : values[values.length - 1]);
+  });
+
+  return req;
+}
+
+function decodeValue(kind: FieldKind | undefined, value: string): unknown {
+  switch (kind) {
+    case "number":
+    case "number[]":
+      return Number(value);
+    case "boolean":
+    case "boolean[]":
+      return value === "true";
+    default:
+      return value;
+  }
+}
+
+// Sets a possibly nested field, e.g. "book.name", creating parents as needed.
+function setField(req: Record<string, unknown>, path: string, value: unknown) {
+  const keys = path.split(".");
+  let obj = req;
+  keys.slice(0, -1).forEach((key) => {
+    if (!isPlainObject(obj[key])) {
+      obj[key] = {};
+    }
+    obj = obj[key] as Record<string, unknown>;
+  });
+  obj[keys[keys.length - 1]] = value;
+}
+
+function jsonResponse(status: number, body: unknown): Response {
+  return new Response(JSON.stringify(body, replacer), {
+    status,
+    headers: { "Content-Type": "application/json" },
+  });
+}
+
+function errorResponse(
+  status: number,
+  code: number,
+  message: string
+): Response {
+  return jsonResponse(status, { code, message, details: [] });
+}
+
+// Handlers can control the error response by throwing an object with a numeric
+// `status` (HTTP status) and `code` (gRPC code).
+function exceptionResponse(err: unknown): Response {
+  const { status, code } = (err ?? {}) as { status?: unknown; code?: unknown };
+  return errorResponse(
+    typeof status === "number" ? status : 500,
+    typeof code === "number" ? code : 2,
+    errorMessage(err)
+  );
+}
+
+function errorMessage(err: unknown): string {
+  const { message } = (err ?? {}) as { message?: unknown };
+  return typeof message === "string" ? message : String(err);
+}
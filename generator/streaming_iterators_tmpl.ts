@@ -0,0 +1,48 @@
+
+/**
+ * fetchStreamingIterator sends a server streaming request and returns the
+ * entities of the response as an async iterable, the request is sent when the
+ * iteration starts and cancelled when it stops early.
+ */
+export async function* fetchStreamingIterator<R>(
+  path: string,
+  init?: InitReq,
+  validator?: Validator
+): AsyncGenerator<R, void, undefined> {
+  const {
+    pathPrefix,
+    fetch: transport = fetch,
+    validateResponse = responseValidation,
+    ...req
+  } = init ?? {};
+  const url = pathPrefix ? `${pathPrefix}${path}` : path;
+  const result = await transport(url, req);
+  if (!result.ok) {
+    const resp = (await result.json()) as { error?: { message?: string } };
+    const errMsg = resp?.error?.message ?? "unknown error";
+    throw new Error(errMsg);
+  }
+
+  if (!result.body) {
+    throw new Error("response does not have a body");
+  }
+
+  const reader = result.body
+    .pipeThrough(new TextDecoderStream())
+    .pipeThrough<R>(getNewLineDelimitedJSONDecodingStream<R>())
+    .getReader();
+  let done = false;
+  try {
+    while (!done) {
+      const next = await reader.read();
+      done = next.done;
+      if (!next.done) {
+        if (validator && validateResponse) validator(next.value);
+        yield next.value;
+      }
+    }
+  } finally {
+    if (!done) await reader.cancel();
+    reader.releaseLock();
+  }
+}
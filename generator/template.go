@@ -6,10 +6,12 @@ import (
 	"log/slog"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig"
+	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
@@ -23,6 +25,36 @@ var serviceTmplScript string
 //go:embed fetch_tmpl.ts
 var fetchTmplScript string
 
+//go:embed router_tmpl.ts
+var routerTmplScript string
+
+//go:embed validators_tmpl.ts
+var validatorsTmplScript string
+
+//go:embed constraints_tmpl.ts
+var constraintsTmplScript string
+
+//go:embed descriptors_tmpl.ts
+var descriptorsTmplScript string
+
+//go:embed message_utils_tmpl.ts
+var messageUtilsTmplScript string
+
+//go:embed field_masks_tmpl.ts
+var fieldMasksTmplScript string
+
+//go:embed pagination_tmpl.ts
+var paginationTmplScript string
+
+//go:embed operations_tmpl.ts
+var operationsTmplScript string
+
+//go:embed resources_tmpl.ts
+var resourcesTmplScript string
+
+//go:embed streaming_iterators_tmpl.ts
+var streamingIteratorsTmplScript string
+
 const fetchTmplHeader = `{{- if not .EnableStylingCheck}}
 /* eslint-disable */
 // @ts-nocheck
@@ -31,13 +63,37 @@ const fetchTmplHeader = `{{- if not .EnableStylingCheck}}
 {{- end}}
 `
 
-var fetchTmpl = fetchTmplHeader + fetchTmplScript
+var fetchTmpl = fetchTmplHeader + fetchTmplScript +
+	"{{- if .GenerateFakeServers}}" + routerTmplScript + "{{- end}}\n" +
+	"{{- if eq .RuntimeValidators \"standalone\"}}" + validatorsTmplScript + "{{- end}}\n" +
+	"{{- if .GenerateConstraintValidators}}" + constraintsTmplScript + "{{- end}}\n" +
+	"{{- if .GenerateDescriptors}}" + descriptorsTmplScript + "{{- end}}\n" +
+	"{{- if .GenerateMessageUtils}}" + messageUtilsTmplScript + "{{- end}}\n" +
+	"{{- if .GenerateUpdateMasks}}" + fieldMasksTmplScript + "{{- end}}\n" +
+	"{{- if .GeneratePageIterators}}" + paginationTmplScript + "{{- end}}\n" +
+	"{{- if .GenerateOperationHelpers}}" + operationsTmplScript + "{{- end}}\n" +
+	"{{- if .GenerateResourceNames}}" + resourcesTmplScript + "{{- end}}\n" +
+	"{{- if .StreamingIterators}}" + streamingIteratorsTmplScript + "{{- end}}\n"
 
 // Data object injected into the templates.
 type TemplateData struct {
 	*data.File
-	EnableStylingCheck bool
-	UseStaticClasses   bool
+	EnableStylingCheck           bool
+	UseStaticClasses             bool
+	GenerateFakeServers          bool
+	RuntimeValidators            string
+	GenerateConstraintValidators bool
+	GenerateFactories            bool
+	GenerateTaggedOneOfs         bool
+	GenerateEnumHelpers          bool
+	GenerateEnumLabels           bool
+	GenerateDescriptors          bool
+	GenerateMessageUtils         bool
+	GenerateUpdateMasks          bool
+	GeneratePageIterators        bool
+	GenerateOperationHelpers     bool
+	GenerateResourceNames        bool
+	StreamingIterators           bool
 }
 
 // ServiceTemplate gets the template for the primary typescript file.
@@ -50,13 +106,42 @@ func ServiceTemplate(r *registry.Registry) *template.Template {
 		"tsType": func(fieldType data.Type) string {
 			return tsType(r, fieldType)
 		},
-		"tsTypeKey":    tsTypeKey(r),
-		"tsTypeDef":    tsTypeDef(r),
-		"renderURL":    renderURL(r),
-		"buildInitReq": buildInitReq,
-		"fieldName":    fieldName(r),
-		"functionCase": functionCase,
-		"escapeJSDoc":  escapeJSDoc,
+		"tsTypeKey":                  tsTypeKey(r),
+		"tsTypeDef":                  tsTypeDef(r),
+		"renderURL":                  renderURL(r),
+		"buildInitReq":               buildInitReq(r),
+		"fieldName":                  fieldName(r),
+		"functionCase":               functionCase,
+		"identifier":                 identifier,
+		"indentBlock":                indentBlock,
+		"escapeJSDoc":                escapeJSDoc,
+		"routePath":                  routePath(r),
+		"routeBody":                  routeBody(r),
+		"routeFields":                routeFields(r),
+		"fieldCheck":                 fieldCheck(r),
+		"fieldSchema":                fieldSchema(r),
+		"oneOfGroups":                oneOfGroups(r),
+		"responseValidator":          responseValidator(r),
+		"fieldConstraints":           fieldConstraints(r),
+		"requiredOneOfs":             requiredOneOfs(r),
+		"messageVariant":             messageVariant(r),
+		"tsInputType":                tsVariantType(r, inputVariant),
+		"tsOutputType":               tsVariantType(r, outputVariant),
+		"fieldDefault":               fieldDefault(r),
+		"defaultsType":               defaultsType(r),
+		"enumEntries":                enumEntries(r),
+		"fieldDescriptor":            fieldDescriptor(r),
+		"fieldSemantics":             fieldSemantics(r),
+		"maskFields":                 maskFields(r),
+		"updateMaskBody":             updateMaskBody(r),
+		"updateMaskBodyType":         updateMaskBodyType(r),
+		"fieldMaskFunction":          fieldMaskFunction(r),
+		"oneOfNames":                 oneOfNames(r),
+		"operationResponseType":      operationResponseType(r),
+		"operationResponseValidator": operationResponseValidator(r),
+		"resourceParts":              resourceParts,
+		"enumStyle":                  func() string { return r.EnumStyle },
+		"openEnumType":               func() string { return openEnumType(r) },
 	})
 
 	t = template.Must(t.Parse(serviceTmplScript))
@@ -78,9 +163,31 @@ func fieldName(r *registry.Registry) func(name string) string {
 	}
 }
 
+// indentBlock indents the lines of a block of declarations by two spaces, leaving blank lines empty,
+// and trims the blank lines around the block.
+func indentBlock(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// identifier returns a name which can be used as a parameter, reserved words are followed by _.
+func identifier(name string) string {
+	if registry.IsReservedWord(name) {
+		return name + "_"
+	}
+	return name
+}
+
 var (
 	// Match {field} or {field=pattern}, and return's param and pattern.
 	pathParamRegexp = regexp.MustCompile(`{([^=}/]+)(?:=([^}]+))?}`)
+	// Match a plain type reference, e.g. UserId or Ids.UserId.
+	typeReferenceRegexp = regexp.MustCompile(`^[A-Za-z_$][\w$.]*$`)
 )
 
 func renderURL(r *registry.Registry) func(method data.Method) string {
@@ -121,17 +228,122 @@ func renderURL(r *registry.Registry) func(method data.Method) string {
 	}
 }
 
-func buildInitReq(method data.Method) string {
-	httpMethod := method.HTTPMethod
-	m := `method: "` + httpMethod + `"`
-	fields := []string{m}
-	if method.HTTPRequestBody == nil || *method.HTTPRequestBody == "*" {
-		fields = append(fields, "body: JSON.stringify(req, fm.replacer)")
-	} else if *method.HTTPRequestBody != "" {
-		fields = append(fields, `body: JSON.stringify(req["`+*method.HTTPRequestBody+`"], fm.replacer)`)
+// buildInitReq returns the fields of the InitReq of a method. The field a body maps to is read by the
+// name of the field in the generated types, e.g. req["bookBody"] unless use_proto_names is set, like
+// the fake servers read it.
+func buildInitReq(r *registry.Registry) func(method data.Method) string {
+	fieldNameFn := fieldName(r)
+	return func(method data.Method) string {
+		httpMethod := method.HTTPMethod
+		m := `method: "` + httpMethod + `"`
+		fields := []string{m}
+		if method.HTTPRequestBody == nil || *method.HTTPRequestBody == "*" {
+			fields = append(fields, "body: JSON.stringify(req, fm.replacer)")
+		} else if *method.HTTPRequestBody != "" {
+			fields = append(fields, `body: JSON.stringify(req["`+fieldNameFn(*method.HTTPRequestBody)+`"], fm.replacer)`)
+		}
+		if method.PathPrefix != "" {
+			// the path prefix of the service is a default, the callers can still override it
+			fields = append(fields, `pathPrefix: initReq?.pathPrefix ?? "`+method.PathPrefix+`"`)
+		}
+
+		return strings.Join(fields, ", ")
+	}
+}
+
+// routePath returns the path template of the method with the variables renamed to match the
+// field names of the generated types, behind the path prefix of the service.
+func routePath(r *registry.Registry) func(method data.Method) string {
+	fieldNameFn := fieldName(r)
+	return func(method data.Method) string {
+		return method.PathPrefix + pathParamRegexp.ReplaceAllStringFunc(method.URL, func(m string) string {
+			sub := pathParamRegexp.FindStringSubmatch(m)
+			if sub[2] == "" {
+				return "{" + fieldNameFn(sub[1]) + "}"
+			}
+			return "{" + fieldNameFn(sub[1]) + "=" + sub[2] + "}"
+		})
 	}
+}
 
-	return strings.Join(fields, ", ")
+// routeBody returns the field the HTTP body maps to, "*" for the whole request and "" for none.
+func routeBody(r *registry.Registry) func(method data.Method) string {
+	fieldNameFn := fieldName(r)
+	return func(method data.Method) string {
+		if method.HTTPRequestBody == nil || *method.HTTPRequestBody == "*" {
+			return "*"
+		}
+		return fieldNameFn(*method.HTTPRequestBody)
+	}
+}
+
+// routeFields returns an object literal mapping the paths of the non string fields that may be
+// set from the URL to their kind, so that the router can decode path and query parameters.
+func routeFields(r *registry.Registry) func(method data.Method) string {
+	fieldNameFn := fieldName(r)
+	return func(method data.Method) string {
+		kinds := make(map[string]string)
+		var walk func(fqType, prefix string, visited map[string]bool)
+		walk = func(fqType, prefix string, visited map[string]bool) {
+			typeInfo, ok := r.Types[fqType]
+			if !ok || typeInfo.Message == nil || visited[fqType] {
+				return
+			}
+			visited[fqType] = true
+			defer delete(visited, fqType)
+
+			for _, f := range typeInfo.Message.Fields {
+				path := prefix + fieldNameFn(f.Name)
+				kind := fieldKind(r, f.Type)
+				switch {
+				case kind != "" && f.IsRepeated:
+					kinds[path] = kind + "[]"
+				case kind != "" && kind != "string":
+					kinds[path] = kind
+				case kind == "" && !f.IsRepeated:
+					walk(f.Type, path+".", visited)
+				}
+			}
+		}
+		walk(method.Input.Type, "", make(map[string]bool))
+
+		paths := make([]string, 0, len(kinds))
+		for p := range kinds {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+
+		entries := make([]string, 0, len(paths))
+		for _, p := range paths {
+			entries = append(entries, fmt.Sprintf(`"%s": "%s"`, p, kinds[p]))
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	}
+}
+
+// fieldKind returns the kind of value a field of the given type holds when it is encoded in a URL,
+// or an empty string for messages.
+func fieldKind(r *registry.Registry, protoType string) string {
+	switch protoType {
+	case "float", "double", "int32", "sint32", "uint32", "fixed32", "sfixed32",
+		".google.protobuf.DoubleValue", ".google.protobuf.FloatValue",
+		".google.protobuf.Int32Value", ".google.protobuf.UInt32Value",
+		".google.protobuf.Int64Value", ".google.protobuf.UInt64Value":
+		return "number"
+	case "bool", ".google.protobuf.BoolValue":
+		return "boolean"
+	}
+	if isScalaType(protoType) || strings.HasPrefix(protoType, ".google.protobuf.") {
+		// Well-known types such as timestamps and durations are encoded as strings.
+		return "string"
+	}
+	if typeInfo, ok := r.Types[protoType]; ok && typeInfo.ProtoType == descriptorpb.FieldDescriptorProto_TYPE_ENUM {
+		if r.UseEnumNumbers {
+			return "number"
+		}
+		return "string"
+	}
+	return ""
 }
 
 // include is the include template functions copied from copied from:
@@ -155,6 +367,10 @@ func escapeJSDoc(s string) string {
 func tsTypeKey(r *registry.Registry) func(field *data.Field) string {
 	return func(field *data.Field) string {
 		name := fieldName(r)(field.Name)
+		if isRequiredKey(field, field.Variant) {
+			// Required fields must be set in requests, even though the gateway omits zero values.
+			return name
+		}
 		if !r.EmitUnpopulated || field.IsOptional {
 			// When EmitUnpopulated is false, the gateway will return undefined for
 			// any zero value, so all fields may be undefined. Optional fields, may
@@ -181,11 +397,36 @@ func tsTypeDef(r *registry.Registry) func(field *data.Field) string {
 }
 
 func tsType(r *registry.Registry, fieldType data.Type) string {
-	info := fieldType.GetType()
+	if field, ok := fieldType.(*data.Field); ok {
+		if typeStr := typeOverride(field); typeStr != "" {
+			return typeStr
+		}
+		if typeStr := resourceNameType(r, field); typeStr != "" {
+			return typeStr
+		}
+	}
+	return tsTypeInfo(r, fieldType.GetType())
+}
+
+// typeOverride returns the type of a field set with the ts_type option, or an empty string. The type
+// of repeated fields is wrapped in parentheses unless it is a plain reference, e.g. (A | B)[].
+func typeOverride(field *data.Field) string {
+	if field.TSType == "" || !field.IsRepeated {
+		return field.TSType
+	}
+	if typeReferenceRegexp.MatchString(field.TSType) {
+		return field.TSType + "[]"
+	}
+	return "(" + field.TSType + ")[]"
+}
+
+func tsTypeInfo(r *registry.Registry, info *data.TypeInfo) string {
 	typeInfo, ok := r.Types[info.Type]
 	if ok && typeInfo.IsMapEntry {
 		keyType := tsType(r, typeInfo.KeyType)
-		valueType := tsType(r, typeInfo.ValueType)
+		valueInfo := typeInfo.ValueType.GetType()
+		valueInfo.Variant = info.Variant
+		valueType := tsTypeInfo(r, valueInfo)
 
 		return fmt.Sprintf("Record<%s, %s>", keyType, valueType)
 	}
@@ -193,12 +434,18 @@ func tsType(r *registry.Registry, fieldType data.Type) string {
 	switch {
 	case mapWellKnownType(info.Type) != "":
 		typeStr = mapWellKnownType(info.Type)
+	case r.StringTimeTypes && (info.Type == ".google.protobuf.Timestamp" || info.Type == ".google.protobuf.Duration"):
+		// the JSON encoding of timestamps and durations is a string
+		typeStr = "string"
 	case strings.Index(info.Type, ".") != 0:
 		typeStr = mapScalaType(info.Type)
 	case !info.IsExternal:
 		typeStr = typeInfo.PackageIdentifier
 	default:
-		typeStr = data.GetModuleName(typeInfo.Package, typeInfo.File) + "." + typeInfo.PackageIdentifier
+		typeStr = r.ModuleIdentifier(typeInfo.Package, typeInfo.File) + "." + typeInfo.PackageIdentifier
+	}
+	if info.Variant != "" && hasVariant(r, info.Type, info.Variant) {
+		typeStr += info.Variant
 	}
 
 	if info.IsRepeated {
@@ -224,6 +471,8 @@ func mapWellKnownType(protoType string) string {
 		return "StructPBValue[]"
 	case ".google.protobuf.Struct":
 		return "{ [key: string]: StructPBValue }"
+	case ".google.protobuf.FieldMask":
+		return "string"
 	}
 	return ""
 }
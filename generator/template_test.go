@@ -1,10 +1,13 @@
 package generator
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 func TestFieldName(t *testing.T) {
@@ -39,6 +42,69 @@ func TestFieldName(t *testing.T) {
 	}
 }
 
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "book", want: "book"},
+		{input: "package", want: "package_"},
+		{input: "delete", want: "delete_"},
+		{input: "fm", want: "fm_"},
+		{input: "Package", want: "Package"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, identifier(tt.input))
+		})
+	}
+}
+
+func TestIndentBlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "single line", input: "export type A = {};", want: "  export type A = {};"},
+		{name: "surrounding blank lines", input: "\n\nexport type A = {};\n\n", want: "  export type A = {};"},
+		{
+			name:  "nested lines",
+			input: "export type A = {\n  a?: string;\n};\n\nexport type B = {};",
+			want:  "  export type A = {\n    a?: string;\n  };\n\n  export type B = {};",
+		},
+		{name: "whitespace only lines", input: "a\n  \nb", want: "  a\n  \n  b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, indentBlock(tt.input))
+		})
+	}
+}
+
+func TestTypeOverride(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *data.Field
+		want  string
+	}{
+		{name: "no override", field: &data.Field{Type: "string"}, want: ""},
+		{name: "override", field: &data.Field{Type: "string", TSType: "UserId"}, want: "UserId"},
+		{name: "repeated", field: &data.Field{Type: "string", TSType: "Ids.UserId", IsRepeated: true}, want: "Ids.UserId[]"},
+		{name: "repeated union", field: &data.Field{Type: "string", TSType: `"a" | "b"`, IsRepeated: true}, want: `("a" | "b")[]`},
+		{
+			name:  "repeated generic",
+			field: &data.Field{Type: "string", TSType: "Branded<string>", IsRepeated: true},
+			want:  "(Branded<string>)[]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, typeOverride(tt.field))
+		})
+	}
+}
+
 func TestEscapeJSDoc(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -88,3 +154,201 @@ func TestEscapeJSDoc(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildInitReq(t *testing.T) {
+	body := "book_body"
+	tests := []struct {
+		name          string
+		useProtoNames bool
+		method        data.Method
+		want          string
+	}{
+		{
+			name:   "whole request body",
+			method: data.Method{HTTPMethod: "POST"},
+			want:   `method: "POST", body: JSON.stringify(req, fm.replacer)`,
+		},
+		{
+			name:   "field body",
+			method: data.Method{HTTPMethod: "PATCH", HTTPRequestBody: &body},
+			want:   `method: "PATCH", body: JSON.stringify(req["bookBody"], fm.replacer)`,
+		},
+		{
+			name:          "field body with proto names",
+			useProtoNames: true,
+			method:        data.Method{HTTPMethod: "PATCH", HTTPRequestBody: &body},
+			want:          `method: "PATCH", body: JSON.stringify(req["book_body"], fm.replacer)`,
+		},
+		{
+			name:   "path prefix",
+			method: data.Method{HTTPMethod: "GET", HTTPRequestBody: new(string), PathPrefix: "/api"},
+			want:   `method: "GET", pathPrefix: initReq?.pathPrefix ?? "/api"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &registry.Registry{Options: registry.Options{UseProtoNames: tt.useProtoNames}}
+			assert.Equal(t, tt.want, buildInitReq(r)(tt.method))
+		})
+	}
+}
+
+func TestRoutePath(t *testing.T) {
+	tests := []struct {
+		useProtoNames bool
+		url           string
+		pathPrefix    string
+		want          string
+	}{
+		{useProtoNames: false, url: "/api/{num_to_increase}", want: "/api/{numToIncrease}"},
+		{useProtoNames: true, url: "/api/{num_to_increase}", want: "/api/{num_to_increase}"},
+		{useProtoNames: false, url: "/v1/{book.book_name=shelves/*/books/*}", want: "/v1/{book.bookName=shelves/*/books/*}"},
+		{useProtoNames: false, url: "/post/{a=first/*}/{c=**}", want: "/post/{a=first/*}/{c=**}"},
+		{useProtoNames: false, url: "/v1/{book_id}", pathPrefix: "/library", want: "/library/v1/{bookId}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			r := &registry.Registry{Options: registry.Options{UseProtoNames: tt.useProtoNames}}
+			got := routePath(r)(data.Method{URL: tt.url, PathPrefix: tt.pathPrefix})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRouteFields(t *testing.T) {
+	inner := &data.Message{Fields: []*data.Field{
+		{Name: "flag", Type: "bool"},
+		{Name: "note", Type: "string"},
+	}}
+	outer := &data.Message{Fields: []*data.Field{
+		{Name: "page_size", Type: "int32"},
+		{Name: "ids", Type: "int64", IsRepeated: true},
+		{Name: "kinds", Type: ".test.Kind", IsRepeated: true},
+		{Name: "limit", Type: ".google.protobuf.UInt32Value"},
+		{Name: "inner", Type: ".test.Inner"},
+		{Name: "self", Type: ".test.Outer"},
+	}}
+	r := &registry.Registry{Types: map[string]*registry.TypeInformation{
+		".test.Outer": {Message: outer},
+		".test.Inner": {Message: inner},
+		".test.Kind":  {ProtoType: descriptorpb.FieldDescriptorProto_TYPE_ENUM},
+	}}
+
+	got := routeFields(r)(data.Method{Input: &data.MethodArgument{Type: ".test.Outer"}})
+	assert.Equal(t,
+		`{"ids": "string[]", "inner.flag": "boolean", "kinds": "string[]", "limit": "number", `+
+			`"pageSize": "number"}`,
+		got)
+}
+
+func TestMessageTaggedTemplate(t *testing.T) {
+	msg := data.NewMessage()
+	msg.Name = "Post"
+	msg.OneOfFieldsNames[0] = "payload"
+	msg.OneOfFieldsGroups[0] = []*data.Field{
+		{Name: "text", Type: "string", IsOneOfField: true},
+		{Name: "image_url", Type: "string", IsOneOfField: true},
+	}
+
+	r := &registry.Registry{Types: map[string]*registry.TypeInformation{}}
+	w := bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "message_tagged", msg))
+
+	out := w.String()
+	assert.Contains(t, out, `export type PostTagged = BasePost & {
+  payload?:
+    | { $case: "text"; text: string }
+    | { $case: "imageUrl"; imageUrl: string };
+};`)
+	assert.Contains(t, out, `$imageUrl !== undefined ? { $case: "imageUrl", imageUrl: $imageUrl } :`)
+	assert.Contains(t, out, `...($payload?.$case === "imageUrl" ? { imageUrl: $payload.imageUrl } : {}),`)
+}
+
+func TestPageIteratorsTemplate(t *testing.T) {
+	service := &data.Service{
+		Name:       "Library",
+		ClientName: "Library",
+		Methods: []*data.Method{
+			{
+				Name:         "ListBooks",
+				TSMethodName: "ListBooks",
+				URL:          "/v1/books",
+				HTTPMethod:   "GET",
+				Input:        &data.MethodArgument{Type: ".test.ListBooksRequest"},
+				Output:       &data.MethodArgument{Type: ".test.ListBooksResponse"},
+				Pagination: &data.Pagination{
+					PageTokenField:     "page_token",
+					NextPageTokenField: "next_page_token",
+					ItemsField:         "books",
+				},
+			},
+		},
+	}
+	r := &registry.Registry{Types: map[string]*registry.TypeInformation{
+		".test.ListBooksRequest":  {PackageIdentifier: "ListBooksRequest"},
+		".test.ListBooksResponse": {PackageIdentifier: "ListBooksResponse"},
+	}}
+	params := map[string]interface{}{"Service": service, "Messages": []*data.Message{}, "PageIterators": true}
+
+	w := bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "service_client", params))
+	out := w.String()
+	assert.Contains(t, out, `return fm.fetchPages(req, (page) => listBooks(page, initReq), "pageToken", "nextPageToken", initReq?.signal);`)
+	assert.Contains(t, out, `return fm.fetchItems(listBooksPages(req, initReq), "books");`)
+	assert.Contains(t, out, `return listBooksAll(req, {...this.initReq, ...initReq});`)
+
+	w = bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "static_service", params))
+	assert.Contains(t, w.String(), `return fm.fetchItems(Library.ListBooksPages(req, initReq), "books");`)
+
+	params["PageIterators"] = false
+	w = bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "service_client", params))
+	assert.NotContains(t, w.String(), "fetchPages")
+}
+
+func TestStreamingIteratorTemplate(t *testing.T) {
+	service := &data.Service{
+		Name:       "Library",
+		ClientName: "LibraryApi",
+		Methods: []*data.Method{
+			{
+				Name:            "WatchBooks",
+				TSMethodName:    "WatchBooks",
+				URL:             "/v1/books:watch",
+				HTTPMethod:      "GET",
+				HTTPRequestBody: new(string),
+				ServerStreaming: true,
+				StreamingStyle:  registry.StreamingStyleAsyncIterable,
+				PathPrefix:      "/library",
+				Input:           &data.MethodArgument{Type: ".test.WatchBooksRequest"},
+				Output:          &data.MethodArgument{Type: ".test.Book"},
+			},
+		},
+	}
+	r := &registry.Registry{Types: map[string]*registry.TypeInformation{
+		".test.WatchBooksRequest": {PackageIdentifier: "WatchBooksRequest"},
+		".test.Book":              {PackageIdentifier: "Book"},
+	}}
+	params := map[string]interface{}{"Service": service, "Messages": []*data.Message{}}
+
+	w := bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "service_client", params))
+	out := w.String()
+	assert.Contains(t, out, `export function watchBooks(req: WatchBooksRequest, initReq?: fm.InitReq): AsyncGenerator<Book, void, undefined> {`)
+	assert.Contains(t, out, `return fm.fetchStreamingIterator<Book>(`)
+	assert.Contains(t, out, `pathPrefix: initReq?.pathPrefix ?? "/library"`)
+	assert.Contains(t, out, `export class LibraryApi {`)
+	assert.NotContains(t, out, "entityNotifier")
+
+	w = bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "static_service", params))
+	out = w.String()
+	assert.Contains(t, out, `export class LibraryApi {`)
+	assert.Contains(t, out, `static WatchBooks(this:void, req: WatchBooksRequest, initReq?: fm.InitReq): AsyncGenerator<Book, void, undefined> {`)
+
+	service.Methods[0].StreamingStyle = registry.StreamingStyleCallback
+	w = bytes.NewBufferString("")
+	assert.NoError(t, ServiceTemplate(r).ExecuteTemplate(w, "static_service", params))
+	assert.Contains(t, w.String(), `return fm.fetchStreamingRequest<Book>(`)
+}
@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// fieldCheck returns the expression for the standalone check of a field, following the same rules
+// for optional keys and null values as tsTypeKey and tsTypeDef.
+func fieldCheck(r *registry.Registry) func(field *data.Field) string {
+	return func(field *data.Field) string {
+		check := typeCheck(r, field)
+		if typeInfo, ok := r.Types[field.Type]; ok && typeInfo.IsMapEntry {
+			check = "fm.checkMap(" + typeCheck(r, typeInfo.ValueType) + ")"
+		} else if field.IsRepeated {
+			check = "fm.checkRepeated(" + check + ")"
+		}
+		if isNullableField(r, field) {
+			check = "fm.nullable(" + check + ")"
+		}
+		if isOptionalField(r, field) {
+			check = "fm.optional(" + check + ")"
+		}
+		return check
+	}
+}
+
+// fieldSchema returns the zod schema of a field, following the same rules for optional keys and
+// null values as tsTypeKey and tsTypeDef.
+func fieldSchema(r *registry.Registry) func(field *data.Field) string {
+	return func(field *data.Field) string {
+		schema := typeSchema(r, field)
+		if typeInfo, ok := r.Types[field.Type]; ok && typeInfo.IsMapEntry {
+			schema = "z.record(" + typeSchema(r, typeInfo.ValueType) + ")"
+		} else if field.IsRepeated {
+			schema = "z.array(" + schema + ")"
+		}
+		if isNullableField(r, field) {
+			schema += ".nullable()"
+		}
+		if isOptionalField(r, field) {
+			schema += ".optional()"
+		}
+		return schema
+	}
+}
+
+// isOptionalField returns whether the key of the field may be absent.
+func isOptionalField(r *registry.Registry, field *data.Field) bool {
+	return !r.EmitUnpopulated || field.IsOptional || field.IsOneOfField
+}
+
+// isNullableField returns whether the field may be null, zero values of messages and lists are
+// emitted as null by the gateway when EmitUnpopulated is true.
+func isNullableField(r *registry.Registry, field *data.Field) bool {
+	return r.EmitUnpopulated && (!isScalaType(field.Type) || field.IsRepeated)
+}
+
+func typeCheck(r *registry.Registry, fieldType data.Type) string {
+	info := fieldType.GetType()
+	switch info.Type {
+	case "string", "uint64", "sint64", "int64", "fixed64", "sfixed64":
+		return "fm.checkString"
+	case "float", "double", "int32", "sint32", "uint32", "fixed32", "sfixed32":
+		return "fm.checkNumber"
+	case "bool":
+		return "fm.checkBoolean"
+	case "bytes":
+		return "fm.checkBytes"
+	case ".google.protobuf.BoolValue":
+		return "fm.nullable(fm.checkBoolean)"
+	case ".google.protobuf.StringValue":
+		return "fm.nullable(fm.checkString)"
+	case ".google.protobuf.DoubleValue",
+		".google.protobuf.FloatValue",
+		".google.protobuf.Int32Value",
+		".google.protobuf.Int64Value",
+		".google.protobuf.UInt32Value",
+		".google.protobuf.UInt64Value":
+		return "fm.nullable(fm.checkNumber)"
+	case ".google.protobuf.Timestamp", ".google.protobuf.Duration":
+		if !r.StringTimeTypes {
+			// the gateway encodes them as strings, which don't match their message types
+			return "fm.checkAny"
+		}
+		return "fm.checkString"
+	case ".google.protobuf.FieldMask":
+		return "fm.checkString"
+	case ".google.protobuf.Struct", ".google.protobuf.Any":
+		return "fm.checkStruct"
+	case ".google.protobuf.ListValue":
+		return "fm.checkList"
+	case ".google.protobuf.Empty":
+		return "fm.checkMessage({})"
+	}
+	if isWellKnownType(info.Type) {
+		return "fm.checkAny"
+	}
+	module, identifier, ok := typeReference(r, info)
+	if !ok {
+		return "fm.checkAny"
+	}
+	return module + "check" + identifier
+}
+
+func typeSchema(r *registry.Registry, fieldType data.Type) string {
+	info := fieldType.GetType()
+	switch info.Type {
+	case "string", "uint64", "sint64", "int64", "fixed64", "sfixed64":
+		return "z.string()"
+	case "float", "double", "int32", "sint32", "uint32", "fixed32", "sfixed32":
+		return "z.number()"
+	case "bool":
+		return "z.boolean()"
+	case "bytes":
+		return "z.union([z.string(), z.instanceof(Uint8Array)])"
+	case ".google.protobuf.BoolValue":
+		return "z.boolean().nullable()"
+	case ".google.protobuf.StringValue":
+		return "z.string().nullable()"
+	case ".google.protobuf.DoubleValue",
+		".google.protobuf.FloatValue",
+		".google.protobuf.Int32Value",
+		".google.protobuf.Int64Value",
+		".google.protobuf.UInt32Value",
+		".google.protobuf.UInt64Value":
+		return "z.number().nullable()"
+	case ".google.protobuf.Timestamp", ".google.protobuf.Duration":
+		if !r.StringTimeTypes {
+			return "z.unknown()"
+		}
+		return "z.string()"
+	case ".google.protobuf.FieldMask":
+		return "z.string()"
+	case ".google.protobuf.Struct", ".google.protobuf.Any":
+		return "z.record(z.unknown())"
+	case ".google.protobuf.ListValue":
+		return "z.array(z.unknown())"
+	case ".google.protobuf.Empty":
+		return "z.object({})"
+	}
+	if isWellKnownType(info.Type) {
+		return "z.unknown()"
+	}
+	module, identifier, ok := typeReference(r, info)
+	if !ok {
+		return "z.unknown()"
+	}
+	return module + identifier + "Schema"
+}
+
+// isWellKnownType returns whether the type is one of the google.protobuf types, which are rendered
+// by the generator rather than imported from generated files.
+func isWellKnownType(protoType string) bool {
+	return strings.HasPrefix(protoType, ".google.protobuf.")
+}
+
+// typeReference returns the identifier of a message or enum along with the module prefix, e.g.
+// "PkgFile.", needed to reference it when it is declared in another file, followed by its namespace
+// when it is declared in one.
+func typeReference(r *registry.Registry, info *data.TypeInfo) (string, string, bool) {
+	typeInfo, ok := r.Types[info.Type]
+	if !ok {
+		return "", "", false
+	}
+	module, identifier := "", typeInfo.PackageIdentifier
+	if info.IsExternal {
+		module = r.ModuleIdentifier(typeInfo.Package, typeInfo.File) + "."
+	}
+	if typeInfo.Namespace != "" {
+		// the helpers of nested types are declared in their namespace, e.g. Outer.checkInner
+		module += typeInfo.Namespace + "."
+		identifier = strings.TrimPrefix(identifier, typeInfo.Namespace+".")
+	}
+	return module, identifier, true
+}
+
+// oneOfGroups returns an array literal listing the field names of each oneof group in a message.
+func oneOfGroups(r *registry.Registry) func(msg *data.Message) string {
+	fieldNameFn := fieldName(r)
+	return func(msg *data.Message) string {
+		indexes := make([]int, 0, len(msg.OneOfFieldsGroups))
+		for idx := range msg.OneOfFieldsGroups {
+			indexes = append(indexes, int(idx))
+		}
+		sort.Ints(indexes)
+
+		groups := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			names := make([]string, 0)
+			//nolint:gosec // G115: oneof indexes originate from int32 values
+			for _, f := range msg.OneOfFieldsGroups[int32(idx)] {
+				names = append(names, fmt.Sprintf("%q", fieldNameFn(f.Name)))
+			}
+			groups = append(groups, "["+strings.Join(names, ", ")+"]")
+		}
+		return "[" + strings.Join(groups, ", ") + "]"
+	}
+}
+
+// responseValidator returns the assert function for the output of a method, or an empty string if
+// no validator is generated for the type.
+func responseValidator(r *registry.Registry) func(arg *data.MethodArgument) string {
+	return func(arg *data.MethodArgument) string {
+		hasValidators := r.RuntimeValidators == registry.RuntimeValidatorsZod ||
+			r.RuntimeValidators == registry.RuntimeValidatorsStandalone
+		if !hasValidators || isWellKnownType(arg.Type) || arg.Operation != nil {
+			return ""
+		}
+		info := arg.GetType()
+		if typeInfo, ok := r.Types[info.Type]; !ok || typeInfo.ProtoType != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+			return ""
+		}
+		module, identifier, _ := typeReference(r, info)
+		return module + "assert" + identifier
+	}
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFieldValidators(t *testing.T) {
+	types := map[string]*registry.TypeInformation{
+		".test.Msg": {
+			PackageIdentifier: "Msg",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		},
+		".other.Ext": {
+			Package:           "other",
+			File:              "other/ext.proto",
+			PackageIdentifier: "Ext",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		},
+		".google.protobuf.Timestamp": {
+			Package:           "google.protobuf",
+			File:              "google/protobuf/timestamp.proto",
+			PackageIdentifier: "Timestamp",
+			ProtoType:         descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		},
+		".test.Msg.LabelsEntry": {
+			IsMapEntry: true,
+			KeyType:    &data.MapEntryType{Type: "string"},
+			ValueType:  &data.MapEntryType{Type: "int32"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		emitUnpopulated bool
+		stringTimeTypes bool
+		field           *data.Field
+		wantType        string
+		wantCheck       string
+		wantSchema      string
+	}{
+		{
+			name:       "scalar",
+			field:      &data.Field{Name: "count", Type: "int32"},
+			wantType:   "number",
+			wantCheck:  "fm.optional(fm.checkNumber)",
+			wantSchema: "z.number().optional()",
+		},
+		{
+			name:            "scalar with emit unpopulated",
+			emitUnpopulated: true,
+			field:           &data.Field{Name: "id", Type: "int64"},
+			wantType:        "string",
+			wantCheck:       "fm.checkString",
+			wantSchema:      "z.string()",
+		},
+		{
+			name:            "optional scalar with emit unpopulated",
+			emitUnpopulated: true,
+			field:           &data.Field{Name: "id", Type: "bool", IsOptional: true},
+			wantType:        "boolean",
+			wantCheck:       "fm.optional(fm.checkBoolean)",
+			wantSchema:      "z.boolean().optional()",
+		},
+		{
+			name:            "message with emit unpopulated",
+			emitUnpopulated: true,
+			field:           &data.Field{Name: "msg", Type: ".test.Msg"},
+			wantType:        "Msg",
+			wantCheck:       "fm.nullable(checkMsg)",
+			wantSchema:      "MsgSchema.nullable()",
+		},
+		{
+			name:       "external repeated message",
+			field:      &data.Field{Name: "exts", Type: ".other.Ext", IsExternal: true, IsRepeated: true},
+			wantType:   "OtherExt.Ext[]",
+			wantCheck:  "fm.optional(fm.checkRepeated(OtherExt.checkExt))",
+			wantSchema: "z.array(OtherExt.ExtSchema).optional()",
+		},
+		{
+			name:       "map",
+			field:      &data.Field{Name: "labels", Type: ".test.Msg.LabelsEntry", IsRepeated: true},
+			wantType:   "Record<string, number>",
+			wantCheck:  "fm.optional(fm.checkMap(fm.checkNumber))",
+			wantSchema: "z.record(z.number()).optional()",
+		},
+		{
+			name:       "wrapper",
+			field:      &data.Field{Name: "name", Type: ".google.protobuf.StringValue"},
+			wantType:   "string | null",
+			wantCheck:  "fm.optional(fm.nullable(fm.checkString))",
+			wantSchema: "z.string().nullable().optional()",
+		},
+		{
+			name:            "repeated duration as strings",
+			stringTimeTypes: true,
+			field:           &data.Field{Name: "gaps", Type: ".google.protobuf.Duration", IsExternal: true, IsRepeated: true},
+			wantType:        "string[]",
+			wantCheck:       "fm.optional(fm.checkRepeated(fm.checkString))",
+			wantSchema:      "z.array(z.string()).optional()",
+		},
+		{
+			name:            "timestamp as string",
+			stringTimeTypes: true,
+			field:           &data.Field{Name: "created", Type: ".google.protobuf.Timestamp", IsExternal: true},
+			wantType:        "string",
+			wantCheck:       "fm.optional(fm.checkString)",
+			wantSchema:      "z.string().optional()",
+		},
+		{
+			// the JSON encoding is a string, which doesn't match the message type
+			name:       "timestamp",
+			field:      &data.Field{Name: "created", Type: ".google.protobuf.Timestamp", IsExternal: true},
+			wantType:   "GoogleProtobufTimestamp.Timestamp",
+			wantCheck:  "fm.optional(fm.checkAny)",
+			wantSchema: "z.unknown().optional()",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &registry.Registry{
+				Options: registry.Options{
+					EmitUnpopulated:   tt.emitUnpopulated,
+					RuntimeValidators: registry.RuntimeValidatorsStandalone,
+					StringTimeTypes:   tt.stringTimeTypes,
+				},
+				Types: types,
+			}
+			assert.Equal(t, tt.wantType, tsType(r, tt.field))
+			assert.Equal(t, tt.wantCheck, fieldCheck(r)(tt.field))
+			assert.Equal(t, tt.wantSchema, fieldSchema(r)(tt.field))
+		})
+	}
+}
+
+func TestOneOfGroups(t *testing.T) {
+	msg := data.NewMessage()
+	msg.OneOfFieldsGroups[1] = []*data.Field{{Name: "c_d"}, {Name: "e"}}
+	msg.OneOfFieldsGroups[0] = []*data.Field{{Name: "a"}, {Name: "b"}}
+
+	r := &registry.Registry{}
+	assert.Equal(t, `[["a", "b"], ["cD", "e"]]`, oneOfGroups(r)(msg))
+}
@@ -0,0 +1,154 @@
+
+/**
+ * Runtime checks used by the generated standalone validators. A Check pushes a
+ * description of each problem it finds onto errors, prefixed with the path of
+ * the offending value.
+ */
+export type Check = (value: unknown, path: string, errors: string[]) => void;
+
+/**
+ * ValidationError is thrown by the generated assert functions, errors lists
+ * every problem found in the value.
+ */
+export class ValidationError extends Error {
+  readonly errors: string[];
+
+  constructor(errors: string[]) {
+    super(errors.join("\n"));
+    this.name = "ValidationError";
+    this.errors = errors;
+    Object.setPrototypeOf(this, ValidationError.prototype);
+  }
+}
+
+// validate runs the check and returns the problems found in the value.
+export function validate(check: Check, value: unknown, path: string): string[] {
+  const errors: string[] = [];
+  check(value, path, errors);
+  return errors;
+}
+
+// assertValid throws a ValidationError if the check finds any problem.
+export function assertValid(check: Check, value: unknown, path: string): void {
+  const errors = validate(check, value, path);
+  if (errors.length > 0) {
+    throw new ValidationError(errors);
+  }
+}
+
+function describeValue(value: unknown): string {
+  if (value === null) return "null";
+  if (Array.isArray(value)) return "array";
+  return typeof value;
+}
+
+function mismatch(
+  expected: string,
+  value: unknown,
+  path: string,
+  errors: string[]
+): void {
+  errors.push(`${path}: expected ${expected}, got ${describeValue(value)}`);
+}
+
+export const checkAny: Check = () => undefined;
+
+export const checkString: Check = (value, path, errors) => {
+  if (typeof value !== "string") mismatch("string", value, path, errors);
+};
+
+export const checkNumber: Check = (value, path, errors) => {
+  if (typeof value !== "number") mismatch("number", value, path, errors);
+};
+
+export const checkBoolean: Check = (value, path, errors) => {
+  if (typeof value !== "boolean") mismatch("boolean", value, path, errors);
+};
+
+// Bytes are base64 strings on the wire and Uint8Arrays once decoded.
+export const checkBytes: Check = (value, path, errors) => {
+  if (typeof value !== "string" && !(value instanceof Uint8Array)) {
+    mismatch("bytes", value, path, errors);
+  }
+};
+
+export const checkStruct: Check = (value, path, errors) => {
+  if (!isPlainObject(value)) mismatch("object", value, path, errors);
+};
+
+export const checkList: Check = (value, path, errors) => {
+  if (!Array.isArray(value)) mismatch("array", value, path, errors);
+};
+
+export function checkEnum(values: readonly (string | number)[]): Check {
+  return (value, path, errors) => {
+    if (values.indexOf(value as string | number) === -1) {
+      errors.push(`${path}: unknown enum value ${JSON.stringify(value)}`);
+    }
+  };
+}
+
+// optional allows the value to be undefined, i.e. the key to be absent.
+export function optional(check: Check): Check {
+  return (value, path, errors) => {
+    if (value !== undefined) check(value, path, errors);
+  };
+}
+
+// nullable allows the value to be null.
+export function nullable(check: Check): Check {
+  return (value, path, errors) => {
+    if (value !== null) check(value, path, errors);
+  };
+}
+
+export function checkRepeated(check: Check): Check {
+  return (value, path, errors) => {
+    if (!Array.isArray(value)) {
+      mismatch("array", value, path, errors);
+      return;
+    }
+    value.forEach((v, i) => check(v, `${path}[${i}]`, errors));
+  };
+}
+
+export function checkMap(check: Check): Check {
+  return (value, path, errors) => {
+    if (!isPlainObject(value)) {
+      mismatch("object", value, path, errors);
+      return;
+    }
+    const obj = value as Record<string, unknown>;
+    Object.keys(obj).forEach((k) =>
+      check(obj[k], `${path}[${JSON.stringify(k)}]`, errors)
+    );
+  };
+}
+
+/**
+ * checkMessage checks each field of a message, unknown fields are ignored.
+ * oneofs lists the groups of fields of which at most one may be set.
+ */
+export function checkMessage(
+  fields: Record<string, Check>,
+  oneofs: string[][] = []
+): Check {
+  return (value, path, errors) => {
+    if (!isPlainObject(value)) {
+      mismatch("object", value, path, errors);
+      return;
+    }
+    const obj = value as Record<string, unknown>;
+    Object.keys(fields).forEach((k) =>
+      fields[k](obj[k], `${path}.${k}`, errors)
+    );
+    oneofs.forEach((group) => {
+      const set = group.filter((k) => obj[k] !== undefined);
+      if (set.length > 1) {
+        errors.push(
+          `${path}: only one of ${group.join(", ")} may be set, got ${set.join(", ")}`
+        );
+      }
+    });
+  };
+}
@@ -16,7 +16,8 @@ import (
 
 func main() {
 	if err := run(); err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "protoc-gen-grpc-gateway-ts: %v\n", err)
+		os.Exit(1)
 	}
 }
 
@@ -30,8 +31,36 @@ func run() error {
 		fetchModuleFilename  = flag.String("fetch_module_filename", "fetch.pb.ts", "name of shard typescript file")
 		tsImportRoots        = flag.String("ts_import_roots", "", "defaults to $(pwd)")
 		tsImportRootAliases  = flag.String("ts_import_root_aliases", "", "use import aliases instead of relative paths")
-
-		enableStylingCheck = flag.Bool("enable_styling_check", false, "TODO")
+		grpcAPIConfiguration = flag.String("grpc_api_configuration", "", "path to a gRPC API configuration YAML file")
+		include              = flag.String("include", "", "globs of the services and methods to generate, separated by ;")
+		exclude              = flag.String("exclude", "", "globs of the services and methods to leave out, separated by ;")
+		visibilityLabels     = flag.String("visibility_labels", "", "google.api.visibility labels to generate, separated by ;")
+
+		enableStylingCheck           = flag.Bool("enable_styling_check", false, "TODO")
+		generateFakeServers          = flag.Bool("generate_fake_servers", false, "generate service interfaces and in-memory routers")
+		runtimeValidators            = flag.String("runtime_validators", "none", "generate runtime validators: none, zod or standalone")
+		stringTimeTypes              = flag.Bool("string_time_types", false, "type timestamps and durations as strings")
+		generateFactories            = flag.Bool("generate_factories", false, "generate factories filling in default values")
+		generateTaggedOneOfs         = flag.Bool("generate_tagged_oneofs", false, "generate oneofs as tagged unions")
+		enumStyle                    = flag.String("enum_style", "enum", "render enums as: enum, union or const_object")
+		nestedStyle                  = flag.String("nested_style", "flat", "render nested types as: flat or namespace")
+		pruneUnreachable             = flag.Bool("prune_unreachable", false, "only generate the types reachable from services")
+		unboundMethods               = flag.String("unbound_methods", "generate", "methods without HTTP rules: generate, skip or error")
+		useEnumNumbers               = flag.Bool("use_enum_numbers", false, "type enum values as numbers")
+		generateEnumHelpers          = flag.Bool("generate_enum_helpers", false, "generate enum value lists and converters")
+		openEnums                    = flag.Bool("open_enums", false, "allow unknown values in union and const_object enums")
+		enumPrefixStrip              = flag.Bool("enum_prefix_strip", false, "strip the enum name prefix from enum keys")
+		generateEnumLabels           = flag.Bool("generate_enum_labels", false, "generate labels and options for enum values")
+		generateDescriptors          = flag.Bool("generate_descriptors", false, "generate message and enum descriptors")
+		generateMessageUtils         = flag.Bool("generate_message_utils", false, "generate equals, clone and merge functions")
+		generateUpdateMasks          = flag.Bool("generate_update_masks", false, "generate update mask helpers for PATCH methods")
+		generatePageIterators        = flag.Bool("generate_page_iterators", false, "generate iterators for paginated List methods")
+		generateOperationHelpers     = flag.Bool("generate_operation_helpers", false, "type long-running operations and wait for them")
+		generateResourceNames        = flag.Bool("generate_resource_names", false, "generate helpers for the names of google.api.resource resources")
+		generateConstraintValidators = flag.Bool("generate_constraint_validators", false,
+			"generate validate functions from buf.validate and protoc-gen-validate rules")
+		warningsAsErrors  = flag.Bool("warnings_as_errors", false, "fail the generation on warnings")
+		diagnosticsReport = flag.String("diagnostics_report", "", "file listing the warnings, printed to stderr when empty")
 
 		logtostderr = flag.Bool("logtostderr", false, "turn on logging to stderr")
 		loglevel    = flag.String("loglevel", "info", "defines the logging level. Values are debug, info, warn, error")
@@ -39,57 +68,101 @@ func run() error {
 
 	flag.Parse()
 
-	req := decodeReq()
+	req, err := decodeReq()
+	if err != nil {
+		return err
+	}
 
+	// from here on errors are reported through the response, protoc prints them and fails
 	paramsMap := getParamsMap(req)
 	for k, v := range paramsMap {
 		if k != "" {
 			if err := flag.CommandLine.Set(k, v); err != nil {
-				return fmt.Errorf("error setting flag %s: %w  [%+v]", k, err, paramsMap)
+				return reportError(fmt.Errorf("error setting flag %s: %w  [%+v]", k, err, paramsMap))
 			}
 		}
 	}
 
 	if err := configureLogging(*logtostderr, *loglevel); err != nil {
-		return err
+		return reportError(err)
 	}
 
 	reg, err := registry.NewRegistry(registry.Options{
-		UseProtoNames:        *useProtoNames,
-		UseStaticClasses:     *useStaticClasses,
-		EnableStylingCheck:   *enableStylingCheck,
-		EmitUnpopulated:      *emitUnpopulated,
-		FetchModuleDirectory: *fetchModuleDirectory,
-		FetchModuleFilename:  *fetchModuleFilename,
-		TSImportRoots:        *tsImportRoots,
-		TSImportRootAliases:  *tsImportRootAliases,
+		UseProtoNames:                *useProtoNames,
+		UseStaticClasses:             *useStaticClasses,
+		EnableStylingCheck:           *enableStylingCheck,
+		EmitUnpopulated:              *emitUnpopulated,
+		GenerateFakeServers:          *generateFakeServers,
+		RuntimeValidators:            *runtimeValidators,
+		StringTimeTypes:              *stringTimeTypes,
+		GenerateConstraintValidators: *generateConstraintValidators,
+		GenerateFactories:            *generateFactories,
+		GenerateTaggedOneOfs:         *generateTaggedOneOfs,
+		EnumStyle:                    *enumStyle,
+		NestedStyle:                  *nestedStyle,
+		UnboundMethods:               *unboundMethods,
+		PruneUnreachable:             *pruneUnreachable,
+		UseEnumNumbers:               *useEnumNumbers,
+		GenerateEnumHelpers:          *generateEnumHelpers,
+		OpenEnums:                    *openEnums,
+		EnumPrefixStrip:              *enumPrefixStrip,
+		GenerateEnumLabels:           *generateEnumLabels,
+		GenerateDescriptors:          *generateDescriptors,
+		GenerateMessageUtils:         *generateMessageUtils,
+		GenerateUpdateMasks:          *generateUpdateMasks,
+		GeneratePageIterators:        *generatePageIterators,
+		GenerateOperationHelpers:     *generateOperationHelpers,
+		GenerateResourceNames:        *generateResourceNames,
+		FetchModuleDirectory:         *fetchModuleDirectory,
+		FetchModuleFilename:          *fetchModuleFilename,
+		TSImportRoots:                *tsImportRoots,
+		TSImportRootAliases:          *tsImportRootAliases,
+		GRPCAPIConfiguration:         *grpcAPIConfiguration,
+		Include:                      *include,
+		Exclude:                      *exclude,
+		VisibilityLabels:             *visibilityLabels,
+		WarningsAsErrors:             *warningsAsErrors,
+		DiagnosticsReport:            *diagnosticsReport,
 	})
 	if err != nil {
-		return fmt.Errorf("error instantiating a new registry: %w", err)
+		return reportError(fmt.Errorf("error instantiating a new registry: %w", err))
 	}
 
 	g, err := generator.New(reg)
 	if err != nil {
-		return fmt.Errorf("error instantiating a new generator: %w", err)
+		return reportError(fmt.Errorf("error instantiating a new generator: %w", err))
 	}
 
 	slog.Debug("Starts generating file request")
 
 	resp, err := g.Generate(req)
 	if err != nil {
-		return fmt.Errorf("error generating output: %w", err)
+		return reportError(fmt.Errorf("error generating output: %w", err))
+	}
+
+	if *diagnosticsReport == "" {
+		for _, warning := range reg.Warnings() {
+			fmt.Fprintln(os.Stderr, warning)
+		}
 	}
 
 	features := uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
 	resp.SupportedFeatures = &features
 
-	encodeResponse(resp)
+	if err := encodeResponse(resp); err != nil {
+		return err
+	}
 
 	slog.Debug("generation finished")
 
 	return nil
 }
 
+// reportError sends an error to protoc, which prints it along with the name of the plugin and fails.
+func reportError(err error) error {
+	return encodeResponse(&pluginpb.CodeGeneratorResponse{Error: proto.String(err.Error())})
+}
+
 func configureLogging(enableLogging bool, levelStr string) error {
 	if enableLogging {
 		level := slog.LevelInfo
@@ -131,26 +204,27 @@ func getParamsMap(req *pluginpb.CodeGeneratorRequest) map[string]string {
 	return paramsMap
 }
 
-func decodeReq() *pluginpb.CodeGeneratorRequest {
+func decodeReq() (*pluginpb.CodeGeneratorRequest, error) {
 	req := &pluginpb.CodeGeneratorRequest{}
 	data, err := io.ReadAll(os.Stdin)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("error reading the code generator request: %w", err)
 	}
 	err = proto.Unmarshal(data, req)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("error decoding the code generator request: %w", err)
 	}
-	return req
+	return req, nil
 }
 
-func encodeResponse(resp proto.Message) {
+func encodeResponse(resp proto.Message) error {
 	data, err := proto.Marshal(resp)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error encoding the code generator response: %w", err)
 	}
 	_, err = os.Stdout.Write(data)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error writing the code generator response: %w", err)
 	}
+	return nil
 }
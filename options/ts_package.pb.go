@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	reflect "reflect"
+	sync "sync"
 )
 
 const (
@@ -20,6 +21,379 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Declaration selects how the type of a message is declared.
+type Declaration int32
+
+const (
+	Declaration_DECLARATION_UNSPECIFIED Declaration = 0
+	// export type Message = { ... }
+	Declaration_DECLARATION_TYPE Declaration = 1
+	// export interface Message { ... }, which can't be used for messages with oneofs.
+	Declaration_DECLARATION_INTERFACE Declaration = 2
+)
+
+// Enum value maps for Declaration.
+var (
+	Declaration_name = map[int32]string{
+		0: "DECLARATION_UNSPECIFIED",
+		1: "DECLARATION_TYPE",
+		2: "DECLARATION_INTERFACE",
+	}
+	Declaration_value = map[string]int32{
+		"DECLARATION_UNSPECIFIED": 0,
+		"DECLARATION_TYPE":        1,
+		"DECLARATION_INTERFACE":   2,
+	}
+)
+
+func (x Declaration) Enum() *Declaration {
+	p := new(Declaration)
+	*p = x
+	return p
+}
+
+func (x Declaration) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Declaration) Descriptor() protoreflect.EnumDescriptor {
+	return file_options_ts_package_proto_enumTypes[0].Descriptor()
+}
+
+func (Declaration) Type() protoreflect.EnumType {
+	return &file_options_ts_package_proto_enumTypes[0]
+}
+
+func (x Declaration) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Declaration.Descriptor instead.
+func (Declaration) EnumDescriptor() ([]byte, []int) {
+	return file_options_ts_package_proto_rawDescGZIP(), []int{0}
+}
+
+// StreamingStyle selects how the responses of server streaming methods are delivered.
+type StreamingStyle int32
+
+const (
+	StreamingStyle_STREAMING_STYLE_UNSPECIFIED StreamingStyle = 0
+	// The method takes a callback called with each response and resolves once the stream ends.
+	StreamingStyle_STREAMING_STYLE_CALLBACK StreamingStyle = 1
+	// The method returns an AsyncIterable of the responses.
+	StreamingStyle_STREAMING_STYLE_ASYNC_ITERABLE StreamingStyle = 2
+)
+
+// Enum value maps for StreamingStyle.
+var (
+	StreamingStyle_name = map[int32]string{
+		0: "STREAMING_STYLE_UNSPECIFIED",
+		1: "STREAMING_STYLE_CALLBACK",
+		2: "STREAMING_STYLE_ASYNC_ITERABLE",
+	}
+	StreamingStyle_value = map[string]int32{
+		"STREAMING_STYLE_UNSPECIFIED":    0,
+		"STREAMING_STYLE_CALLBACK":       1,
+		"STREAMING_STYLE_ASYNC_ITERABLE": 2,
+	}
+)
+
+func (x StreamingStyle) Enum() *StreamingStyle {
+	p := new(StreamingStyle)
+	*p = x
+	return p
+}
+
+func (x StreamingStyle) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamingStyle) Descriptor() protoreflect.EnumDescriptor {
+	return file_options_ts_package_proto_enumTypes[1].Descriptor()
+}
+
+func (StreamingStyle) Type() protoreflect.EnumType {
+	return &file_options_ts_package_proto_enumTypes[1]
+}
+
+func (x StreamingStyle) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamingStyle.Descriptor instead.
+func (StreamingStyle) EnumDescriptor() ([]byte, []int) {
+	return file_options_ts_package_proto_rawDescGZIP(), []int{1}
+}
+
+type TSFileOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path of the generated file in the output directory, e.g. "api/users.ts", replacing the name of
+	// the proto file followed by .pb.ts.
+	OutputPath string `protobuf:"bytes,1,opt,name=output_path,json=outputPath,proto3" json:"output_path,omitempty"`
+	// Identifier the generated file is imported as by the files depending on it, e.g. UsersApi,
+	// replacing the package followed by the name of the proto file.
+	ModuleName string `protobuf:"bytes,2,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+}
+
+func (x *TSFileOptions) Reset() {
+	*x = TSFileOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_options_ts_package_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TSFileOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TSFileOptions) ProtoMessage() {}
+
+func (x *TSFileOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_options_ts_package_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TSFileOptions.ProtoReflect.Descriptor instead.
+func (*TSFileOptions) Descriptor() ([]byte, []int) {
+	return file_options_ts_package_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TSFileOptions) GetOutputPath() string {
+	if x != nil {
+		return x.OutputPath
+	}
+	return ""
+}
+
+func (x *TSFileOptions) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+type TSMessageOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Leaves the message out of the generated file, it can't be used by the fields and methods which
+	// are generated. The types nested in the message are still generated.
+	Skip bool `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
+	// Identifier of the message, replacing the name of the message joined to those of its parents.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Declares the fields of the message as readonly.
+	Readonly bool `protobuf:"varint,3,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	// Declaration of the type of the message, a type by default.
+	Declaration Declaration `protobuf:"varint,4,opt,name=declaration,proto3,enum=grpc.gateway.protoc_gen_grpc_gateway_ts.options.Declaration" json:"declaration,omitempty"`
+}
+
+func (x *TSMessageOptions) Reset() {
+	*x = TSMessageOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_options_ts_package_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TSMessageOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TSMessageOptions) ProtoMessage() {}
+
+func (x *TSMessageOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_options_ts_package_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TSMessageOptions.ProtoReflect.Descriptor instead.
+func (*TSMessageOptions) Descriptor() ([]byte, []int) {
+	return file_options_ts_package_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TSMessageOptions) GetSkip() bool {
+	if x != nil {
+		return x.Skip
+	}
+	return false
+}
+
+func (x *TSMessageOptions) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TSMessageOptions) GetReadonly() bool {
+	if x != nil {
+		return x.Readonly
+	}
+	return false
+}
+
+func (x *TSMessageOptions) GetDeclaration() Declaration {
+	if x != nil {
+		return x.Declaration
+	}
+	return Declaration_DECLARATION_UNSPECIFIED
+}
+
+type TSServiceOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the class holding the methods of the service, replacing the name of the service, or
+	// the name of the service followed by Client with use_static_classes=false.
+	ClientName string `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	// Path prefix of the requests of the service, e.g. "/api", used when the InitReq doesn't set one.
+	PathPrefix string `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+}
+
+func (x *TSServiceOptions) Reset() {
+	*x = TSServiceOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_options_ts_package_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TSServiceOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TSServiceOptions) ProtoMessage() {}
+
+func (x *TSServiceOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_options_ts_package_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TSServiceOptions.ProtoReflect.Descriptor instead.
+func (*TSServiceOptions) Descriptor() ([]byte, []int) {
+	return file_options_ts_package_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TSServiceOptions) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *TSServiceOptions) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+type TSMethodOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the method, replacing the name of the RPC.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Names of the additional bindings of the method in order, replacing the name of the method
+	// followed by the HTTP method, e.g. SayHelloPost2.
+	BindingNames []string `protobuf:"bytes,2,rep,name=binding_names,json=bindingNames,proto3" json:"binding_names,omitempty"`
+	// Leaves the method out of the generated file.
+	Skip bool `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	// How the responses of a server streaming method are delivered, with a callback by default.
+	StreamingStyle StreamingStyle `protobuf:"varint,4,opt,name=streaming_style,json=streamingStyle,proto3,enum=grpc.gateway.protoc_gen_grpc_gateway_ts.options.StreamingStyle" json:"streaming_style,omitempty"`
+}
+
+func (x *TSMethodOptions) Reset() {
+	*x = TSMethodOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_options_ts_package_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TSMethodOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TSMethodOptions) ProtoMessage() {}
+
+func (x *TSMethodOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_options_ts_package_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TSMethodOptions.ProtoReflect.Descriptor instead.
+func (*TSMethodOptions) Descriptor() ([]byte, []int) {
+	return file_options_ts_package_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TSMethodOptions) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TSMethodOptions) GetBindingNames() []string {
+	if x != nil {
+		return x.BindingNames
+	}
+	return nil
+}
+
+func (x *TSMethodOptions) GetSkip() bool {
+	if x != nil {
+		return x.Skip
+	}
+	return false
+}
+
+func (x *TSMethodOptions) GetStreamingStyle() StreamingStyle {
+	if x != nil {
+		return x.StreamingStyle
+	}
+	return StreamingStyle_STREAMING_STYLE_UNSPECIFIED
+}
+
 var file_options_ts_package_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.FileOptions)(nil),
@@ -29,12 +403,147 @@ var file_options_ts_package_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,50000,opt,name=ts_package",
 		Filename:      "options/ts_package.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*TSFileOptions)(nil),
+		Field:         50006,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_file",
+		Tag:           "bytes,50006,opt,name=ts_file",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*TSMessageOptions)(nil),
+		Field:         50007,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message",
+		Tag:           "bytes,50007,opt,name=ts_message",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*TSServiceOptions)(nil),
+		Field:         50008,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_service",
+		Tag:           "bytes,50008,opt,name=ts_service",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*TSMethodOptions)(nil),
+		Field:         50009,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method",
+		Tag:           "bytes,50009,opt,name=ts_method",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50001,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.display_name",
+		Tag:           "bytes,50001,opt,name=display_name",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50002,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.description",
+		Tag:           "bytes,50002,opt,name=description",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50003,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.hidden",
+		Tag:           "varint,50003,opt,name=hidden",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50004,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_type",
+		Tag:           "bytes,50004,opt,name=ts_type",
+		Filename:      "options/ts_package.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50005,
+		Name:          "grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_import",
+		Tag:           "bytes,50005,opt,name=ts_import",
+		Filename:      "options/ts_package.proto",
+	},
 }
 
 // Extension fields to descriptorpb.FileOptions.
 var (
+	// Module the generated file is imported from by the files depending on it, e.g. "@app/api/users".
+	//
 	// optional string ts_package = 50000;
 	E_TsPackage = &file_options_ts_package_proto_extTypes[0]
+	// Options of the file generated for a proto file.
+	//
+	// optional grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSFileOptions ts_file = 50006;
+	E_TsFile = &file_options_ts_package_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// Options of the types generated for a message.
+	//
+	// optional grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMessageOptions ts_message = 50007;
+	E_TsMessage = &file_options_ts_package_proto_extTypes[2]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// Options of the class generated for a service.
+	//
+	// optional grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSServiceOptions ts_service = 50008;
+	E_TsService = &file_options_ts_package_proto_extTypes[3]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// Options of the functions generated for a method.
+	//
+	// optional grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMethodOptions ts_method = 50009;
+	E_TsMethod = &file_options_ts_package_proto_extTypes[4]
+)
+
+// Extension fields to descriptorpb.EnumValueOptions.
+var (
+	// Human readable label of the value, used by the generated labels and options.
+	//
+	// optional string display_name = 50001;
+	E_DisplayName = &file_options_ts_package_proto_extTypes[5]
+	// Description of the value, the leading comment of the value is used when not set.
+	//
+	// optional string description = 50002;
+	E_Description = &file_options_ts_package_proto_extTypes[6]
+	// Hides the value from the generated options, e.g. for UNSPECIFIED values.
+	//
+	// optional bool hidden = 50003;
+	E_Hidden = &file_options_ts_package_proto_extTypes[7]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// TypeScript type of the field, e.g. UserId, replacing the type of each value of repeated fields.
+	// It must describe the JSON encoding of the field, so it can only be used on scalar fields other
+	// than bytes, and on google.protobuf.Timestamp, Duration, FieldMask, Struct, Value and ListValue
+	// fields.
+	//
+	// optional string ts_type = 50004;
+	E_TsType = &file_options_ts_package_proto_extTypes[8]
+	// Module the type of ts_type is imported from, e.g. "@app/ids" or "./ids", relative paths being
+	// resolved from the generated file. The leading identifier of ts_type is imported, e.g. Ids for
+	// Ids.UserId.
+	//
+	// optional string ts_import = 50005;
+	E_TsImport = &file_options_ts_package_proto_extTypes[9]
 )
 
 var File_options_ts_package_proto protoreflect.FileDescriptor
@@ -46,27 +555,168 @@ var file_options_ts_package_proto_rawDesc = []byte{
 	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
 	0x5f, 0x74, 0x73, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x20, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x3d, 0x0a,
-	0x0a, 0x74, 0x73, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
-	0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd0, 0x86, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x74, 0x73, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x42, 0x34, 0x5a, 0x32,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x70, 0x75, 0x70, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x72, 0x70, 0x63, 0x2d,
-	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2d, 0x74, 0x73, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x51, 0x0a,
+	0x0d, 0x54, 0x53, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x22, 0xb6, 0x01, 0x0a, 0x10, 0x54, 0x53, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x61, 0x64, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x72, 0x65, 0x61, 0x64, 0x6f, 0x6e, 0x6c, 0x79, 0x12, 0x5e, 0x0a, 0x0b, 0x64, 0x65, 0x63,
+	0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x3c,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x5f, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x74, 0x73, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x2e, 0x44, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x64, 0x65,
+	0x63, 0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x10, 0x54, 0x53, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22,
+	0xc8, 0x01, 0x0a, 0x0f, 0x54, 0x53, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x69, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c,
+	0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x6b, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70,
+	0x12, 0x68, 0x0a, 0x0f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74,
+	0x79, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x3f, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x5f,
+	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x5f, 0x74, 0x73, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x52, 0x0e, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x2a, 0x5b, 0x0a, 0x0b, 0x44, 0x65,
+	0x63, 0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x17, 0x44, 0x45, 0x43,
+	0x4c, 0x41, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x43, 0x4c, 0x41, 0x52,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15,
+	0x44, 0x45, 0x43, 0x4c, 0x41, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x49, 0x4e, 0x54, 0x45,
+	0x52, 0x46, 0x41, 0x43, 0x45, 0x10, 0x02, 0x2a, 0x73, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x1b, 0x53, 0x54, 0x52,
+	0x45, 0x41, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x59, 0x4c, 0x45, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x54,
+	0x52, 0x45, 0x41, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x59, 0x4c, 0x45, 0x5f, 0x43, 0x41,
+	0x4c, 0x4c, 0x42, 0x41, 0x43, 0x4b, 0x10, 0x01, 0x12, 0x22, 0x0a, 0x1e, 0x53, 0x54, 0x52, 0x45,
+	0x41, 0x4d, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x59, 0x4c, 0x45, 0x5f, 0x41, 0x53, 0x59, 0x4e,
+	0x43, 0x5f, 0x49, 0x54, 0x45, 0x52, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x02, 0x3a, 0x3d, 0x0a, 0x0a,
+	0x74, 0x73, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd0, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x74, 0x73, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x3a, 0x77, 0x0a, 0x07, 0x74,
+	0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd6, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x5f, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x5f, 0x74, 0x73, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x54,
+	0x53, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x06, 0x74, 0x73,
+	0x46, 0x69, 0x6c, 0x65, 0x3a, 0x83, 0x01, 0x0a, 0x0a, 0x74, 0x73, 0x5f, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd7, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x5f, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x5f, 0x74, 0x73, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x54,
+	0x53, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x09, 0x74, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x3a, 0x83, 0x01, 0x0a, 0x0a, 0x74,
+	0x73, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd8, 0x86, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x41, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x5f, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x74, 0x73, 0x2e, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x54, 0x53, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x09, 0x74, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x3a, 0x7f, 0x0a, 0x09, 0x74, 0x73, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x1e, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd9, 0x86,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x40, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x67, 0x61, 0x74,
+	0x65, 0x77, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x5f, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x74, 0x73, 0x2e,
+	0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x54, 0x53, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x08, 0x74, 0x73, 0x4d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x3a, 0x46, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69,
+	0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x3a, 0x45, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x3a, 0x3b, 0x0a, 0x06, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75,
+	0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x3a, 0x38, 0x0a,
+	0x07, 0x74, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd4, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x74, 0x73, 0x54, 0x79, 0x70, 0x65, 0x3a, 0x3c, 0x0a, 0x09, 0x74, 0x73, 0x5f, 0x69, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0xd5, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x73, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x70, 0x75, 0x70, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d,
+	0x67, 0x65, 0x6e, 0x2d, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x2d, 0x74, 0x73, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
+var (
+	file_options_ts_package_proto_rawDescOnce sync.Once
+	file_options_ts_package_proto_rawDescData = file_options_ts_package_proto_rawDesc
+)
+
+func file_options_ts_package_proto_rawDescGZIP() []byte {
+	file_options_ts_package_proto_rawDescOnce.Do(func() {
+		file_options_ts_package_proto_rawDescData = protoimpl.X.CompressGZIP(file_options_ts_package_proto_rawDescData)
+	})
+	return file_options_ts_package_proto_rawDescData
+}
+
+var file_options_ts_package_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_options_ts_package_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_options_ts_package_proto_goTypes = []interface{}{
-	(*descriptorpb.FileOptions)(nil), // 0: google.protobuf.FileOptions
+	(Declaration)(0),                      // 0: grpc.gateway.protoc_gen_grpc_gateway_ts.options.Declaration
+	(StreamingStyle)(0),                   // 1: grpc.gateway.protoc_gen_grpc_gateway_ts.options.StreamingStyle
+	(*TSFileOptions)(nil),                 // 2: grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSFileOptions
+	(*TSMessageOptions)(nil),              // 3: grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMessageOptions
+	(*TSServiceOptions)(nil),              // 4: grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSServiceOptions
+	(*TSMethodOptions)(nil),               // 5: grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMethodOptions
+	(*descriptorpb.FileOptions)(nil),      // 6: google.protobuf.FileOptions
+	(*descriptorpb.MessageOptions)(nil),   // 7: google.protobuf.MessageOptions
+	(*descriptorpb.ServiceOptions)(nil),   // 8: google.protobuf.ServiceOptions
+	(*descriptorpb.MethodOptions)(nil),    // 9: google.protobuf.MethodOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 10: google.protobuf.EnumValueOptions
+	(*descriptorpb.FieldOptions)(nil),     // 11: google.protobuf.FieldOptions
 }
 var file_options_ts_package_proto_depIdxs = []int32{
-	0, // 0: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_package:extendee -> google.protobuf.FileOptions
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	0, // [0:1] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMessageOptions.declaration:type_name -> grpc.gateway.protoc_gen_grpc_gateway_ts.options.Declaration
+	1,  // 1: grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMethodOptions.streaming_style:type_name -> grpc.gateway.protoc_gen_grpc_gateway_ts.options.StreamingStyle
+	6,  // 2: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_package:extendee -> google.protobuf.FileOptions
+	6,  // 3: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_file:extendee -> google.protobuf.FileOptions
+	7,  // 4: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message:extendee -> google.protobuf.MessageOptions
+	8,  // 5: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_service:extendee -> google.protobuf.ServiceOptions
+	9,  // 6: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method:extendee -> google.protobuf.MethodOptions
+	10, // 7: grpc.gateway.protoc_gen_grpc_gateway_ts.options.display_name:extendee -> google.protobuf.EnumValueOptions
+	10, // 8: grpc.gateway.protoc_gen_grpc_gateway_ts.options.description:extendee -> google.protobuf.EnumValueOptions
+	10, // 9: grpc.gateway.protoc_gen_grpc_gateway_ts.options.hidden:extendee -> google.protobuf.EnumValueOptions
+	11, // 10: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_type:extendee -> google.protobuf.FieldOptions
+	11, // 11: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_import:extendee -> google.protobuf.FieldOptions
+	2,  // 12: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_file:type_name -> grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSFileOptions
+	3,  // 13: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message:type_name -> grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMessageOptions
+	4,  // 14: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_service:type_name -> grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSServiceOptions
+	5,  // 15: grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method:type_name -> grpc.gateway.protoc_gen_grpc_gateway_ts.options.TSMethodOptions
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	12, // [12:16] is the sub-list for extension type_name
+	2,  // [2:12] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_options_ts_package_proto_init() }
@@ -74,18 +724,70 @@ func file_options_ts_package_proto_init() {
 	if File_options_ts_package_proto != nil {
 		return
 	}
+	if !protoimpl.UnsafeEnabled {
+		file_options_ts_package_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TSFileOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_options_ts_package_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TSMessageOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_options_ts_package_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TSServiceOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_options_ts_package_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TSMethodOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_options_ts_package_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   0,
-			NumExtensions: 1,
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 10,
 			NumServices:   0,
 		},
 		GoTypes:           file_options_ts_package_proto_goTypes,
 		DependencyIndexes: file_options_ts_package_proto_depIdxs,
+		EnumInfos:         file_options_ts_package_proto_enumTypes,
+		MessageInfos:      file_options_ts_package_proto_msgTypes,
 		ExtensionInfos:    file_options_ts_package_proto_extTypes,
 	}.Build()
 	File_options_ts_package_proto = out.File
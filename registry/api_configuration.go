@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gopkg.in/yaml.v3"
+)
+
+// loadHTTPRules reads the HTTP rules of a gRPC API configuration YAML file, as accepted by the
+// grpc_api_configuration parameter of grpc-gateway, keyed by the fully qualified name of their method.
+// The rules are located at their line in the file.
+func loadHTTPRules(fileName string) (map[string][]*httpBinding, error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading gRPC API configuration %s", fileName)
+	}
+
+	var root yaml.Node
+	var config struct {
+		HTTP interface{} `yaml:"http"`
+	}
+	if err := yaml.Unmarshal(contents, &root); err != nil {
+		return nil, errors.Wrapf(err, "error parsing gRPC API configuration %s", fileName)
+	}
+	if err := root.Decode(&config); err != nil {
+		return nil, errors.Wrapf(err, "error parsing gRPC API configuration %s", fileName)
+	}
+
+	rules := make(map[string][]*httpBinding)
+	if config.HTTP == nil {
+		return rules, nil
+	}
+	jsonContents, err := json.Marshal(config.HTTP)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing gRPC API configuration %s", fileName)
+	}
+	httpConfig := &annotations.Http{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(jsonContents, httpConfig); err != nil {
+		return nil, errors.Wrapf(err, "error parsing the http rules of gRPC API configuration %s", fileName)
+	}
+
+	ruleNodes := yamlValue(yamlValue(&root, "http"), "rules")
+	for idx, rule := range httpConfig.GetRules() {
+		selector := "." + strings.TrimSpace(rule.GetSelector())
+		if strings.ContainsAny(selector, "*, ") {
+			return nil, errors.Errorf("selector %q in %s must specify a single service method without wildcards",
+				rule.GetSelector(), fileName)
+		}
+		loc := sourceLocation{file: fileName}
+		if ruleNodes != nil && idx < len(ruleNodes.Content) {
+			loc.line, loc.column = ruleNodes.Content[idx].Line, ruleNodes.Content[idx].Column
+		}
+		rules[selector] = append(rules[selector], &httpBinding{rule: rule, loc: loc})
+	}
+	return rules, nil
+}
+
+// yamlValue returns the value of a key of a YAML mapping, or nil when the node isn't a mapping or
+// doesn't have the key. The mapping of a document is looked up in its content.
+func yamlValue(node *yaml.Node, key string) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// checkHTTPRuleSelectors reports the HTTP rules of the gRPC API configuration whose selector doesn't
+// name a method of the analysed files, which grpc-gateway rejects as well.
+func (r *Registry) checkHTTPRuleSelectors(files []*descriptorpb.FileDescriptorProto) {
+	methods := make(map[string]bool)
+	for _, f := range files {
+		for _, service := range f.GetService() {
+			for _, method := range service.GetMethod() {
+				methods["."+f.GetPackage()+"."+service.GetName()+"."+method.GetName()] = true
+			}
+		}
+	}
+	selectors := make([]string, 0, len(r.httpRules))
+	for selector := range r.httpRules {
+		if !methods[selector] {
+			selectors = append(selectors, selector)
+		}
+	}
+	sort.Strings(selectors)
+	for _, selector := range selectors {
+		for _, binding := range r.httpRules[selector] {
+			r.report(SeverityError, binding.loc, "HTTP rule selector %s matches no method", binding.rule.GetSelector())
+		}
+	}
+}
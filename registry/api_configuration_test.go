@@ -0,0 +1,212 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAPIConfiguration writes a gRPC API configuration to a temporary file and returns its path.
+func writeAPIConfiguration(t *testing.T, contents string) string {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "api.yaml")
+	require.NoError(t, os.WriteFile(fileName, []byte(contents), 0o600))
+	return fileName
+}
+
+func TestLoadHTTPRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		wantRules map[string][]string
+		wantLines map[string][]int
+		wantErr   string
+	}{
+		{
+			name:      "no http section",
+			config:    "type: google.api.Service\n",
+			wantRules: map[string][]string{},
+		},
+		{
+			name: "selectors",
+			config: `type: google.api.Service
+http:
+  rules:
+    - selector: library.Library.GetBook
+      get: /v1/{name=books/*}
+    - selector: " library.Library.ListBooks "
+      get: /v1/books
+    - selector: library.Library.GetBook
+      get: /v2/{name=books/*}
+`,
+			wantRules: map[string][]string{
+				".library.Library.GetBook":   {"/v1/{name=books/*}", "/v2/{name=books/*}"},
+				".library.Library.ListBooks": {"/v1/books"},
+			},
+			wantLines: map[string][]int{
+				".library.Library.GetBook":   {4, 8},
+				".library.Library.ListBooks": {6},
+			},
+		},
+		{
+			name: "wildcard",
+			config: `http:
+  rules:
+    - selector: library.Library.*
+      get: /v1/books
+`,
+			wantErr: `selector "library.Library.*"`,
+		},
+		{
+			name: "several selectors",
+			config: `http:
+  rules:
+    - selector: library.Library.GetBook, library.Library.ListBooks
+      get: /v1/books
+`,
+			wantErr: "must specify a single service method without wildcards",
+		},
+		{
+			name:    "invalid rule",
+			config:  "http:\n  rules: {}\n",
+			wantErr: "error parsing the http rules",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := loadHTTPRules(writeAPIConfiguration(t, tt.config))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			gotRules := make(map[string][]string)
+			gotLines := make(map[string][]int)
+			for selector, bindings := range rules {
+				gotRules[selector] = make([]string, 0, len(bindings))
+				for _, binding := range bindings {
+					gotRules[selector] = append(gotRules[selector], binding.rule.GetGet())
+					gotLines[selector] = append(gotLines[selector], binding.loc.line)
+				}
+			}
+			assert.Equal(t, tt.wantRules, gotRules)
+			if tt.wantLines != nil {
+				assert.Equal(t, tt.wantLines, gotLines)
+			}
+		})
+	}
+}
+
+func TestLoadHTTPRulesMissingFile(t *testing.T) {
+	_, err := loadHTTPRules(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "error reading gRPC API configuration")
+}
+
+const configuredService = `
+name: "library.proto"
+package: "library"
+message_type { name: "Book" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+service {
+  name: "Library"
+  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+    options { [google.api.http] { get: "/v1/{name}" additional_bindings { get: "/v1/books/{name}" } } } }
+  method { name: "ListBooks" input_type: ".library.Book" output_type: ".library.Book" }
+}
+`
+
+func TestAPIConfigurationBindings(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		wantBindings []string
+		wantErrors   []string
+	}{
+		{
+			name: "annotation first",
+			config: `http:
+  rules:
+    - selector: library.Library.GetBook
+      get: /v2/{name}
+      additional_bindings:
+        - get: /v2/books/{name}
+    - selector: library.Library.GetBook
+      post: /v3/{name}
+    - selector: library.Library.ListBooks
+      get: /v1/books
+`,
+			wantBindings: []string{
+				"GetBook GET /v1/{name}",
+				"GetBook GET /v1/books/{name}",
+				"GetBook GET /v2/{name}",
+				"GetBook GET /v2/books/{name}",
+				"GetBook POST /v3/{name}",
+				"ListBooks GET /v1/books",
+			},
+		},
+		{
+			name: "unknown selectors",
+			config: `http:
+  rules:
+    - selector: library.Library.ListBooks
+      get: /v1/books
+    - selector: library.Library.DeleteBook
+      delete: /v1/{name}
+    - selector: library.Shelves.GetShelf
+      get: /v1/shelves/{name}
+`,
+			wantErrors: []string{
+				"HTTP rule selector library.Library.DeleteBook matches no method",
+				"HTTP rule selector library.Shelves.GetShelf matches no method",
+			},
+		},
+		{
+			name: "invalid rule",
+			config: `http:
+  rules:
+    - selector: library.Library.ListBooks
+      get: /v1/authors/{title}
+`,
+			wantErrors: []string{"library.Library.ListBooks: path variable title: no field title in library.Book"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := writeAPIConfiguration(t, tt.config)
+			r, filesData, err := analyse(t, Options{GRPCAPIConfiguration: fileName}, newRequest(t, configuredService))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Empty(t, warningMessages(r))
+			if err != nil {
+				return
+			}
+			bindings := make([]string, 0)
+			for _, method := range filesData["library.proto"].Services[0].Methods {
+				bindings = append(bindings, method.Name+" "+method.HTTPMethod+" "+method.URL)
+			}
+			assert.Equal(t, tt.wantBindings, bindings)
+		})
+	}
+}
+
+func TestAPIConfigurationLocations(t *testing.T) {
+	fileName := writeAPIConfiguration(t, `http:
+  rules:
+    - selector: library.Library.ListBooks
+      get: /v1/books
+      additional_bindings:
+        - get: /v1/authors/{title}
+    - selector: library.Library.DeleteBook
+      delete: /v1/{name}
+`)
+	_, _, err := analyse(t, Options{GRPCAPIConfiguration: fileName}, newRequest(t, configuredService))
+	var diagnostics Diagnostics
+	require.ErrorAs(t, err, &diagnostics)
+	require.Len(t, diagnostics, 2)
+	// additional bindings are located at their rule
+	assert.Equal(t, fileName+":3:7: library.Library.ListBooks: path variable title: no field title in library.Book",
+		diagnostics[0].String())
+	assert.Equal(t, fileName+":7:7: HTTP rule selector library.Library.DeleteBook matches no method",
+		diagnostics[1].String())
+}
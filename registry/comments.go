@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Numbers of the fields of the descriptors which lead to enum values in the paths of the source
+// code info.
+const (
+	fileMessageTypeField   = 4
+	fileEnumTypeField      = 5
+	messageNestedTypeField = 3
+	messageEnumTypeField   = 4
+	enumValueField         = 2
+)
+
+// analyseComments records the leading comments of the enum values of a file, keyed by the fully
+// qualified name of their enum followed by their name.
+func (r *Registry) analyseComments(f *descriptorpb.FileDescriptorProto) {
+	if r.comments == nil {
+		r.comments = make(map[string]string)
+	}
+	locations := make(map[string]string)
+	for _, loc := range f.GetSourceCodeInfo().GetLocation() {
+		if comment := formatComment(loc.GetLeadingComments()); comment != "" {
+			locations[pathKey(loc.GetPath())] = comment
+		}
+	}
+	if len(locations) == 0 {
+		return
+	}
+
+	prefix := ""
+	if f.GetPackage() != "" {
+		prefix = "." + f.GetPackage()
+	}
+	for i, enum := range f.GetEnumType() {
+		r.analyseEnumComments(locations, prefix+"."+enum.GetName(), childPath(nil, fileEnumTypeField, i), enum)
+	}
+	for i, message := range f.GetMessageType() {
+		r.analyseMessageComments(locations, prefix+"."+message.GetName(), childPath(nil, fileMessageTypeField, i), message)
+	}
+}
+
+func (r *Registry) analyseMessageComments(
+	locations map[string]string, fqName string, path []int32, message *descriptorpb.DescriptorProto) {
+	for i, enum := range message.GetEnumType() {
+		r.analyseEnumComments(locations, fqName+"."+enum.GetName(), childPath(path, messageEnumTypeField, i), enum)
+	}
+	for i, nested := range message.GetNestedType() {
+		r.analyseMessageComments(locations, fqName+"."+nested.GetName(), childPath(path, messageNestedTypeField, i), nested)
+	}
+}
+
+func (r *Registry) analyseEnumComments(
+	locations map[string]string, fqName string, path []int32, enum *descriptorpb.EnumDescriptorProto) {
+	for i, value := range enum.GetValue() {
+		if comment, ok := locations[pathKey(childPath(path, enumValueField, i))]; ok {
+			r.comments[fqName+"."+value.GetName()] = comment
+		}
+	}
+}
+
+func childPath(path []int32, field int32, index int) []int32 {
+	child := make([]int32, 0, len(path)+2)
+	child = append(child, path...)
+	//nolint:gosec // G115: indexes of descriptors are int32 values in source code info
+	return append(child, field, int32(index))
+}
+
+func pathKey(path []int32) string {
+	parts := make([]string, 0, len(path))
+	for _, p := range path {
+		parts = append(parts, strconv.Itoa(int(p)))
+	}
+	return strings.Join(parts, ".")
+}
+
+// formatComment joins the lines of a comment into a single line.
+func formatComment(comment string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(comment, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
@@ -0,0 +1,229 @@
+package registry
+
+import (
+	"log/slog"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+)
+
+// protovalidateIgnoreAlways is the value of buf.validate.Ignore which skips every rule of a field.
+const protovalidateIgnoreAlways = 3
+
+// supportedRules lists the rules, shared by buf.validate and protoc-gen-validate, that can be checked
+// on the client. Other rules are ignored.
+var supportedRules = map[string]bool{
+	"const":        true,
+	"len":          true,
+	"min_len":      true,
+	"max_len":      true,
+	"pattern":      true,
+	"prefix":       true,
+	"suffix":       true,
+	"contains":     true,
+	"not_contains": true,
+	"in":           true,
+	"not_in":       true,
+	"email":        true,
+	"hostname":     true,
+	"uri":          true,
+	"uuid":         true,
+	"lt":           true,
+	"lte":          true,
+	"gt":           true,
+	"gte":          true,
+	"defined_only": true,
+	"min_items":    true,
+	"max_items":    true,
+	"unique":       true,
+	"min_pairs":    true,
+	"max_pairs":    true,
+}
+
+// unsupportedTypeRules lists the rule groups that can't be checked against the JSON representation of
+// a field.
+var unsupportedTypeRules = map[string]bool{
+	"bytes":     true,
+	"any":       true,
+	"duration":  true,
+	"timestamp": true,
+}
+
+// Names of the buf.validate and protoc-gen-validate extensions. They are read through the descriptors
+// of the request, see extension.
+const (
+	protovalidateFieldExtension   = "buf.validate.field"
+	protovalidateMessageExtension = "buf.validate.message"
+	protovalidateOneofExtension   = "buf.validate.oneof"
+	pgvRulesExtension             = "validate.rules"
+	pgvDisabledExtension          = "validate.disabled"
+	pgvIgnoredExtension           = "validate.ignored"
+	pgvRequiredExtension          = "validate.required"
+)
+
+// analyseFieldConstraints reads the buf.validate and protoc-gen-validate rules declared on a field.
+func (r *Registry) analyseFieldConstraints(fieldData *data.Field, opts *descriptorpb.FieldOptions) {
+	if opts == nil {
+		return
+	}
+	if rules, ok := r.extension(opts, protovalidateFieldExtension); ok {
+		readFieldRules(fieldData, rules.Message())
+	}
+	if rules, ok := r.extension(opts, pgvRulesExtension); ok {
+		readFieldRules(fieldData, rules.Message())
+	}
+}
+
+// isValidationDisabled returns whether the rules of the fields of a message are turned off.
+func (r *Registry) isValidationDisabled(opts *descriptorpb.MessageOptions) bool {
+	if opts == nil {
+		return false
+	}
+	if rules, ok := r.extension(opts, protovalidateMessageExtension); ok && boolField(rules.Message(), "disabled") {
+		return true
+	}
+	disabled, _ := r.extension(opts, pgvDisabledExtension)
+	ignored, _ := r.extension(opts, pgvIgnoredExtension)
+	return isTrue(disabled) || isTrue(ignored)
+}
+
+// isOneOfRequired returns whether one of the fields of a oneof must be set.
+func (r *Registry) isOneOfRequired(opts *descriptorpb.OneofOptions) bool {
+	if opts == nil {
+		return false
+	}
+	if rules, ok := r.extension(opts, protovalidateOneofExtension); ok && boolField(rules.Message(), "required") {
+		return true
+	}
+	required, _ := r.extension(opts, pgvRequiredExtension)
+	return isTrue(required)
+}
+
+// boolField returns the value of a bool field of a message, false when the message has no such field.
+func boolField(msg protoreflect.Message, name protoreflect.Name) bool {
+	fd := msg.Descriptor().Fields().ByName(name)
+	return fd != nil && fd.Kind() == protoreflect.BoolKind && msg.Get(fd).Bool()
+}
+
+// isTrue returns whether the value of a bool extension is set to true.
+func isTrue(v protoreflect.Value) bool {
+	b, ok := v.Interface().(bool)
+	return ok && b
+}
+
+// readFieldRules reads either a buf.validate.FieldConstraints or a validate.FieldRules message, both
+// use the same names for the rules the client supports so they are walked through reflection.
+func readFieldRules(fieldData *data.Field, rules protoreflect.Message) {
+	rangeSetFields(rules, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+		switch name := string(fd.Name()); name {
+		case "required":
+			fieldData.IsRequired = fieldData.IsRequired || v.Bool()
+		case "ignore_empty":
+			fieldData.IgnoreEmpty = fieldData.IgnoreEmpty || v.Bool()
+		case "ignore":
+			if v.Enum() == protovalidateIgnoreAlways {
+				skipFieldRules(fieldData)
+			} else if v.Enum() != 0 {
+				fieldData.IgnoreEmpty = true
+			}
+		case "skipped", "skip":
+			if v.Bool() {
+				skipFieldRules(fieldData)
+			}
+		case "message":
+			// protoc-gen-validate declares required and skip in a group of their own
+			readFieldRules(fieldData, v.Message())
+		default:
+			if fd.Message() != nil {
+				readTypeRules(fieldData, name, v.Message())
+			}
+		}
+	})
+}
+
+// readTypeRules reads the rules of a specific type, e.g. buf.validate.StringRules.
+func readTypeRules(fieldData *data.Field, typeName string, rules protoreflect.Message) {
+	if unsupportedTypeRules[typeName] {
+		slog.Debug("validation rules are not supported on the client", slog.String("type", typeName))
+		return
+	}
+	rangeSetFields(rules, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+		name := string(fd.Name())
+		switch {
+		case name == "ignore_empty":
+			fieldData.IgnoreEmpty = fieldData.IgnoreEmpty || v.Bool()
+		case name == "items":
+			items := &data.Field{}
+			readFieldRules(items, v.Message())
+			fieldData.ItemConstraints = append(fieldData.ItemConstraints, items.Constraints...)
+		case !supportedRules[name]:
+			slog.Debug("validation rule is not supported on the client", slog.String("rule", typeName+"."+name))
+		default:
+			value, ok := constraintValue(fd, v)
+			if ok {
+				fieldData.Constraints = append(fieldData.Constraints, &data.Constraint{ID: typeName + "." + name, Value: value})
+			}
+		}
+	})
+}
+
+// skipFieldRules drops the rules read so far, for fields whose validation is turned off.
+func skipFieldRules(fieldData *data.Field) {
+	fieldData.IsRequired = false
+	fieldData.Constraints = nil
+	fieldData.ItemConstraints = nil
+}
+
+// rangeSetFields calls fn for each populated field of msg, in declaration order so that the
+// generated code is stable.
+func rangeSetFields(msg protoreflect.Message, fn func(protoreflect.FieldDescriptor, protoreflect.Value)) {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if msg.Has(fd) {
+			fn(fd, msg.Get(fd))
+		}
+	}
+}
+
+// constraintValue converts the argument of a rule to a plain go value, false booleans and messages
+// are dropped.
+func constraintValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, bool) {
+	if fd.IsList() {
+		list := v.List()
+		values := make([]interface{}, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			if value, ok := scalarValue(fd.Kind(), list.Get(i)); ok {
+				values = append(values, value)
+			}
+		}
+		return values, true
+	}
+	value, ok := scalarValue(fd.Kind(), v)
+	if b, isBool := value.(bool); isBool && !b {
+		return nil, false
+	}
+	return value, ok
+}
+
+func scalarValue(kind protoreflect.Kind, v protoreflect.Value) (interface{}, bool) {
+	switch kind {
+	case protoreflect.BoolKind:
+		return v.Bool(), true
+	case protoreflect.StringKind:
+		return v.String(), true
+	case protoreflect.EnumKind:
+		return int64(v.Enum()), true
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int(), true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint(), true
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float(), true
+	default:
+		return nil, false
+	}
+}
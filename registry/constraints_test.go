@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// protovalidateFile and pgvFile declare the parts of buf/validate/validate.proto and
+// validate/validate.proto read by the tests.
+const (
+	protovalidateFile = `name: "buf/validate/validate.proto" package: "buf.validate"
+	  dependency: "google/protobuf/descriptor.proto"
+	  message_type { name: "FieldConstraints"
+	    field { name: "string" number: 14 type: TYPE_MESSAGE type_name: ".buf.validate.StringRules" label: LABEL_OPTIONAL }
+	    field { name: "required" number: 25 type: TYPE_BOOL label: LABEL_OPTIONAL } }
+	  message_type { name: "StringRules" field { name: "min_len" number: 2 type: TYPE_UINT64 label: LABEL_OPTIONAL } }
+	  message_type { name: "MessageConstraints" field { name: "disabled" number: 1 type: TYPE_BOOL label: LABEL_OPTIONAL } }
+	  extension { name: "field" number: 1159 type: TYPE_MESSAGE type_name: ".buf.validate.FieldConstraints"
+	    extendee: ".google.protobuf.FieldOptions" label: LABEL_OPTIONAL }
+	  extension { name: "message" number: 1159 type: TYPE_MESSAGE type_name: ".buf.validate.MessageConstraints"
+	    extendee: ".google.protobuf.MessageOptions" label: LABEL_OPTIONAL }`
+	pgvFile = `name: "validate/validate.proto" package: "validate"
+	  dependency: "google/protobuf/descriptor.proto"
+	  message_type { name: "FieldRules"
+	    field { name: "string" number: 14 type: TYPE_MESSAGE type_name: ".validate.StringRules" label: LABEL_OPTIONAL } }
+	  message_type { name: "StringRules" field { name: "max_len" number: 3 type: TYPE_UINT64 label: LABEL_OPTIONAL } }
+	  extension { name: "rules" number: 1071 type: TYPE_MESSAGE type_name: ".validate.FieldRules"
+	    extendee: ".google.protobuf.FieldOptions" label: LABEL_OPTIONAL }
+	  extension { name: "disabled" number: 1071 type: TYPE_BOOL extendee: ".google.protobuf.MessageOptions" label: LABEL_OPTIONAL }`
+	validatedFile = `name: "book.proto" package: "book"
+	  message_type { name: "Book" field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }`
+)
+
+// constraintsRequest returns a request generating validatedFile along with the given validate files,
+// the way protoc sends the files imported by the files to generate.
+func constraintsRequest(t *testing.T, files ...string) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+	req := newRequest(t, append(files, validatedFile)...)
+	descriptor := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+	req.ProtoFile = append([]*descriptorpb.FileDescriptorProto{descriptor}, req.ProtoFile...)
+	req.FileToGenerate = []string{"book.proto"}
+	return req
+}
+
+// appendMessage appends a length delimited field to an encoded message.
+func appendMessage(b []byte, number protowire.Number, value []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(b, number, protowire.BytesType), value)
+}
+
+// appendVarint appends a varint field to an encoded message.
+func appendVarint(b []byte, number protowire.Number, value uint64) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(b, number, protowire.VarintType), value)
+}
+
+// setUnknownOptions sets the options of the book message and of its title field to unknown fields,
+// like the options set with extensions the plugin has no Go types for.
+func setUnknownOptions(req *pluginpb.CodeGeneratorRequest, messageOptions, fieldOptions []byte) {
+	book := req.ProtoFile[len(req.ProtoFile)-1].MessageType[0]
+	book.Options = &descriptorpb.MessageOptions{}
+	book.Options.ProtoReflect().SetUnknown(messageOptions)
+	book.Field[0].Options = &descriptorpb.FieldOptions{}
+	book.Field[0].Options.ProtoReflect().SetUnknown(fieldOptions)
+}
+
+func TestFieldConstraintsOptions(t *testing.T) {
+	protovalidateRules := appendMessage(nil, 1159,
+		appendVarint(appendMessage(nil, 14, appendVarint(nil, 2, 3)), 25, 1))
+	pgvRules := appendMessage(nil, 1071, appendMessage(nil, 14, appendVarint(nil, 3, 20)))
+	tests := []struct {
+		name            string
+		files           []string
+		fieldOptions    []byte
+		wantRequired    bool
+		wantConstraints []*data.Constraint
+	}{
+		{
+			name:            "buf.validate",
+			files:           []string{protovalidateFile},
+			fieldOptions:    protovalidateRules,
+			wantRequired:    true,
+			wantConstraints: []*data.Constraint{{ID: "string.min_len", Value: uint64(3)}},
+		},
+		{
+			name:            "protoc-gen-validate",
+			files:           []string{pgvFile},
+			fieldOptions:    pgvRules,
+			wantConstraints: []*data.Constraint{{ID: "string.max_len", Value: uint64(20)}},
+		},
+		{
+			name:         "both",
+			files:        []string{protovalidateFile, pgvFile},
+			fieldOptions: append(append([]byte{}, protovalidateRules...), pgvRules...),
+			wantRequired: true,
+			wantConstraints: []*data.Constraint{
+				{ID: "string.min_len", Value: uint64(3)},
+				{ID: "string.max_len", Value: uint64(20)},
+			},
+		},
+		{
+			// the options can't set an extension of a file that isn't imported
+			name:         "undeclared extension",
+			fieldOptions: protovalidateRules,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := constraintsRequest(t, tt.files...)
+			setUnknownOptions(req, nil, tt.fieldOptions)
+			_, filesData, err := analyse(t, Options{GenerateConstraintValidators: true}, req)
+			require.NoError(t, err)
+			title := filesData["book.proto"].Messages[0].Fields[0]
+			assert.Equal(t, tt.wantRequired, title.IsRequired)
+			assert.Equal(t, tt.wantConstraints, title.Constraints)
+		})
+	}
+}
+
+func TestValidationDisabledOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		messageOptions []byte
+		want           bool
+	}{
+		{name: "buf.validate", messageOptions: appendMessage(nil, 1159, appendVarint(nil, 1, 1)), want: true},
+		{name: "protoc-gen-validate", messageOptions: appendVarint(nil, 1071, 1), want: true},
+		{name: "protoc-gen-validate set to false", messageOptions: appendVarint(nil, 1071, 0)},
+		{name: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := constraintsRequest(t, protovalidateFile, pgvFile)
+			setUnknownOptions(req, tt.messageOptions, nil)
+			_, filesData, err := analyse(t, Options{GenerateConstraintValidators: true}, req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filesData["book.proto"].Messages[0].IsValidationDisabled)
+		})
+	}
+}
+
+func TestLinkedExtension(t *testing.T) {
+	// extensions with Go types linked into the plugin are decoded along with the request
+	r, err := NewRegistry(Options{})
+	require.NoError(t, err)
+	require.NoError(t, r.analyseExtensionTypes(constraintsRequest(t)))
+	opts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(opts, annotations.E_Http, &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/books"}})
+
+	value, ok := r.extension(opts, "google.api.http")
+	require.True(t, ok)
+	assert.Equal(t, "/v1/books", value.Message().Interface().(*annotations.HttpRule).GetGet())
+	_, ok = r.extension(opts, "buf.validate.field")
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Severities of diagnostics.
+const (
+	// SeverityError fails the generation.
+	SeverityError = "error"
+	// SeverityWarning is printed or written to the diagnostics report, the generation goes on unless
+	// warnings are treated as errors.
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in the input files, located with the source code info of the file
+// when it is available.
+type Diagnostic struct {
+	// Severity is either SeverityError or SeverityWarning
+	Severity string
+	// File is the name of the file the problem was found in
+	File string
+	// Line and Column are the position of the problem, starting at 1, they are 0 when unknown
+	Line   int
+	Column int
+	// Message describes the problem
+	Message string
+}
+
+// String formats the diagnostic as file:line:column: message, like protoc does, warnings are
+// prefixed with warning.
+func (d *Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location += fmt.Sprintf(":%d:%d", d.Line, d.Column)
+	}
+	if d.Severity == SeverityWarning {
+		return location + ": warning: " + d.Message
+	}
+	return location + ": " + d.Message
+}
+
+// Diagnostics is a list of problems found in the input files, the errors are returned as an error
+// by Analyse.
+type Diagnostics []*Diagnostic
+
+// Error returns the diagnostics with one per line.
+func (d Diagnostics) Error() string {
+	lines := make([]string, 0, len(d))
+	for _, diagnostic := range d {
+		lines = append(lines, diagnostic.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sourceLocation is the location of an element of an analysed file, used to report
+// diagnostics. The line and column locate elements of files without source code info, like the gRPC
+// API configuration.
+type sourceLocation struct {
+	file   string
+	path   []int32
+	line   int
+	column int
+}
+
+// analyseSourceLocations records the spans of the source code info of a file keyed by their path.
+func (r *Registry) analyseSourceLocations(f *descriptorpb.FileDescriptorProto) {
+	if r.spans == nil {
+		r.spans = make(map[string]map[string][]int32)
+	}
+	spans := make(map[string][]int32)
+	for _, loc := range f.GetSourceCodeInfo().GetLocation() {
+		spans[pathKey(loc.GetPath())] = loc.GetSpan()
+	}
+	r.spans[f.GetName()] = spans
+}
+
+// report records a diagnostic at a location of an analysed file, the location of the closest
+// parent element is used when the element itself has no span. Warnings are recorded as errors when
+// WarningsAsErrors is set.
+func (r *Registry) report(severity string, loc sourceLocation, format string, args ...interface{}) {
+	if severity == SeverityWarning && r.WarningsAsErrors {
+		severity = SeverityError
+	}
+	diagnostic := &Diagnostic{
+		Severity: severity,
+		File:     loc.file,
+		Line:     loc.line,
+		Column:   loc.column,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	for n := len(loc.path); n > 0; n-- {
+		if span, ok := r.spans[loc.file][pathKey(loc.path[:n])]; ok && len(span) >= 2 {
+			diagnostic.Line = int(span[0]) + 1
+			diagnostic.Column = int(span[1]) + 1
+			break
+		}
+	}
+	if severity == SeverityWarning {
+		slog.Debug("found a warning", slog.String("warning", diagnostic.String()))
+		r.warnings = append(r.warnings, diagnostic)
+		return
+	}
+	r.diagnostics = append(r.diagnostics, diagnostic)
+}
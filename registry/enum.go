@@ -1,7 +1,13 @@
 package registry
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/options"
+	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -9,25 +15,90 @@ func (r *Registry) analyseEnumType(
 	fileData *data.File,
 	packageName, fileName string,
 	parents []string,
+	loc sourceLocation,
 	enum *descriptorpb.EnumDescriptorProto) {
 	packageIdentifier := r.getNameOfPackageLevelIdentifier(parents, enum.GetName())
 	fqName := r.getFullQualifiedName(packageName, parents, enum.GetName())
 	protoType := descriptorpb.FieldDescriptorProto_TYPE_ENUM
-	r.Types[fqName] = &TypeInformation{
+	typeInfo := &TypeInformation{
 		FullyQualifiedName: fqName,
 		Package:            packageName,
 		File:               fileName,
 		PackageIdentifier:  packageIdentifier,
 		LocalIdentifier:    enum.GetName(),
 		ProtoType:          protoType,
+		EnumValues:         make(map[int32]string),
 	}
+	r.Types[fqName] = typeInfo
 
 	enumData := data.NewEnum()
 	enumData.Name = packageIdentifier
+	enumData.FQType = fqName
+	r.declareEnum(fileName, parents, typeInfo, enumData)
+
+	keys := make(map[string]string)
+	for idx, e := range enum.GetValue() {
+		key := r.enumKey(enum.GetName(), e.GetName())
+		if other, ok := keys[key]; ok && r.IsFileToGenerate(fileName) {
+			//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+			r.report(SeverityError, loc.child(enumValueField, int32(idx)),
+				"%s: values %s and %s both have the key %s once their prefix is stripped",
+				strings.TrimPrefix(fqName, "."), other, e.GetName(), key)
+		}
+		keys[key] = e.GetName()
 
-	for _, e := range enum.GetValue() {
 		enumData.Values = append(enumData.Values, e.GetName())
+		enumData.Numbers = append(enumData.Numbers, e.GetNumber())
+		enumData.Keys = append(enumData.Keys, key)
+		displayName, description, hidden := r.enumValueDisplay(fqName, e)
+		enumData.DisplayNames = append(enumData.DisplayNames, displayName)
+		enumData.Descriptions = append(enumData.Descriptions, description)
+		enumData.Hidden = append(enumData.Hidden, hidden)
+		if _, ok := typeInfo.EnumValues[e.GetNumber()]; !ok {
+			typeInfo.EnumValues[e.GetNumber()] = e.GetName()
+		}
+	}
+
+	if len(enumData.Values) > 0 {
+		typeInfo.EnumDefault = enumData.Values[0]
+		typeInfo.EnumDefaultKey = enumData.Keys[0]
 	}
 
 	fileData.Enums = append(fileData.Enums, enumData)
 }
+
+// enumKey returns the typescript key of an enum value, which is its name without the prefix derived
+// from the enum name when EnumPrefixStrip is set, e.g. RED for the value COLOR_RED of Color. The name
+// is kept as is when stripping the prefix wouldn't leave a valid identifier.
+func (r *Registry) enumKey(enumName, valueName string) string {
+	if !r.EnumPrefixStrip {
+		return valueName
+	}
+	key, ok := strings.CutPrefix(valueName, strcase.ToScreamingSnake(enumName)+"_")
+	if !ok || key == "" || unicode.IsDigit(rune(key[0])) {
+		return valueName
+	}
+	return key
+}
+
+// enumValueDisplay returns the display_name, description and hidden options of an enum value, the
+// description falls back to the leading comment of the value.
+func (r *Registry) enumValueDisplay(
+	fqEnumName string, value *descriptorpb.EnumValueDescriptorProto) (string, string, bool) {
+	opts := value.GetOptions()
+	var displayName, description string
+	var hidden bool
+	if proto.HasExtension(opts, options.E_DisplayName) {
+		displayName, _ = proto.GetExtension(opts, options.E_DisplayName).(string)
+	}
+	if proto.HasExtension(opts, options.E_Description) {
+		description, _ = proto.GetExtension(opts, options.E_Description).(string)
+	}
+	if proto.HasExtension(opts, options.E_Hidden) {
+		hidden, _ = proto.GetExtension(opts, options.E_Hidden).(bool)
+	}
+	if description == "" {
+		description = r.comments[fqEnumName+"."+value.GetName()]
+	}
+	return displayName, description, hidden
+}
@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumKeyCollisions(t *testing.T) {
+	tests := []struct {
+		name            string
+		enumPrefixStrip bool
+		file            string
+		wantKeys        []string
+		wantErrors      []string
+	}{
+		{
+			name:            "stripped prefix",
+			enumPrefixStrip: true,
+			file: `name: "color.proto" package: "color"
+			  enum_type { name: "Color" value { name: "COLOR_UNSPECIFIED" number: 0 } value { name: "COLOR_RED" number: 1 } }`,
+			wantKeys: []string{"UNSPECIFIED", "RED"},
+		},
+		{
+			name: "kept prefix",
+			file: `name: "color.proto" package: "color"
+			  enum_type { name: "Color" value { name: "COLOR_RED" number: 0 } value { name: "RED" number: 1 } }`,
+			wantKeys: []string{"COLOR_RED", "RED"},
+		},
+		{
+			name:            "collision",
+			enumPrefixStrip: true,
+			file: `name: "color.proto" package: "color"
+			  enum_type { name: "Color" value { name: "COLOR_RED" number: 0 } value { name: "RED" number: 1 } }`,
+			wantErrors: []string{"color.Color: values COLOR_RED and RED both have the key RED once their prefix is stripped"},
+		},
+		{
+			name:            "nested collision",
+			enumPrefixStrip: true,
+			file: `name: "paint.proto" package: "paint"
+			  message_type { name: "Paint"
+			    enum_type { name: "Finish" value { name: "FINISH_MATTE" number: 0 } value { name: "MATTE" number: 1 } } }`,
+			wantErrors: []string{"paint.Paint.Finish: values FINISH_MATTE and MATTE both have the key MATTE once their prefix is stripped"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, filesData, err := analyse(t, Options{EnumPrefixStrip: tt.enumPrefixStrip}, newRequest(t, tt.file))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Empty(t, warningMessages(r))
+			if err != nil {
+				return
+			}
+			for _, fileData := range filesData {
+				assert.Equal(t, tt.wantKeys, fileData.Enums[0].Keys)
+			}
+		})
+	}
+}
+
+func TestEnumKeyCollisionLocation(t *testing.T) {
+	req := newRequest(t, `name: "color.proto" package: "color"
+	  enum_type { name: "Color" value { name: "COLOR_RED" number: 0 } value { name: "RED" number: 1 } }
+	  source_code_info { location { path: [5, 0] span: [2, 0, 30] } location { path: [5, 0, 2, 1] span: [4, 2, 10] } }`)
+	_, _, err := analyse(t, Options{EnumPrefixStrip: true}, req)
+	var diagnostics Diagnostics
+	require.ErrorAs(t, err, &diagnostics)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, "color.proto:5:3: color.Color: values COLOR_RED and RED both have the key RED once their prefix is stripped",
+		diagnostics[0].String())
+}
+
+func TestEnumKeyCollisionOfImportedFile(t *testing.T) {
+	// the collision doesn't matter when the file isn't generated
+	req := newRequest(t, `name: "color.proto" package: "color"
+	  enum_type { name: "Color" value { name: "COLOR_RED" number: 0 } value { name: "RED" number: 1 } }`,
+		`name: "paint.proto" package: "paint" dependency: "color.proto"
+	  message_type { name: "Paint" field { name: "color" number: 1 type: TYPE_ENUM type_name: ".color.Color" label: LABEL_OPTIONAL } }`)
+	req.FileToGenerate = []string{"paint.proto"}
+	r, _, err := analyse(t, Options{EnumPrefixStrip: true}, req)
+	require.NoError(t, err)
+	assert.Empty(t, warningMessages(r))
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// analyseExtensionTypes builds the types of the extensions declared by the files of the request, which
+// read the options of third party extensions like buf.validate.field without depending on the Go
+// packages generated for them.
+func (r *Registry) analyseExtensionTypes(req *pluginpb.CodeGeneratorRequest) error {
+	files, err := protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(
+		&descriptorpb.FileDescriptorSet{File: req.GetProtoFile()})
+	if err != nil {
+		return errors.Wrap(err, "error reading the descriptors of the request")
+	}
+	r.extensionTypes = dynamicpb.NewTypes(files)
+	return nil
+}
+
+// extension returns the value of an extension of options, e.g. buf.validate.field, and whether it is
+// set. The extensions whose Go types are linked into the plugin are decoded along with the request,
+// the others are kept in the unknown fields of the options and are decoded with the descriptor of the
+// extension found in the files of the request.
+func (r *Registry) extension(opts proto.Message, name protoreflect.FullName) (protoreflect.Value, bool) {
+	msg := opts.ProtoReflect()
+	if !msg.IsValid() {
+		return protoreflect.Value{}, false
+	}
+	var value protoreflect.Value
+	found := false
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() && fd.FullName() == name {
+			value, found = v, true
+		}
+		return !found
+	})
+	if found || len(msg.GetUnknown()) == 0 || r.extensionTypes == nil {
+		return value, found
+	}
+
+	xt, err := r.extensionTypes.FindExtensionByName(name)
+	if err != nil {
+		// the file declaring the extension isn't imported, so the options can't set it
+		return protoreflect.Value{}, false
+	}
+	decoded := msg.Type().New()
+	unmarshal := proto.UnmarshalOptions{Resolver: r.extensionTypes}
+	if err := unmarshal.Unmarshal(msg.GetUnknown(), decoded.Interface()); err != nil {
+		return protoreflect.Value{}, false
+	}
+	if !decoded.Has(xt.TypeDescriptor()) {
+		return protoreflect.Value{}, false
+	}
+	return decoded.Get(xt.TypeDescriptor()), true
+}
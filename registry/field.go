@@ -2,6 +2,8 @@ package registry
 
 import (
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -54,7 +56,8 @@ func (r *Registry) getFieldType(f *descriptorpb.FieldDescriptorProto) string {
 func (r *Registry) analyseField(
 	fileData *data.File,
 	msgData *data.Message,
-	packageName string,
+	packageName, fileName string,
+	loc sourceLocation,
 	f *descriptorpb.FieldDescriptorProto) {
 	fqTypeName := r.getFieldType(f)
 	isExternal := r.isExternalDependenciesOutsidePackage(fqTypeName, packageName)
@@ -75,6 +78,13 @@ func (r *Registry) analyseField(
 		}
 	}
 
+	r.analyseFieldConstraints(fieldData, f.GetOptions())
+	analyseFieldBehavior(fieldData, f.GetOptions())
+	r.analyseFieldTypeOverride(fileName, fieldData, f, loc)
+	if r.GenerateResourceNames {
+		analyseFieldResourceReference(fileData, fieldData, f.GetOptions())
+	}
+
 	msgData.Fields = append(msgData.Fields, fieldData)
 
 	if !fieldData.IsOneOfField {
@@ -103,3 +113,24 @@ func (r *Registry) analyseField(
 
 	fileData.TrackPackageNonScalarType(fieldData)
 }
+
+// analyseFieldBehavior reads the google.api.field_behavior annotations of a field.
+func analyseFieldBehavior(fieldData *data.Field, opts *descriptorpb.FieldOptions) {
+	if opts == nil || !proto.HasExtension(opts, annotations.E_FieldBehavior) {
+		return
+	}
+	behaviors, _ := proto.GetExtension(opts, annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	for _, behavior := range behaviors {
+		switch behavior {
+		case annotations.FieldBehavior_REQUIRED:
+			fieldData.IsInputRequired = true
+		case annotations.FieldBehavior_OUTPUT_ONLY:
+			fieldData.IsOutputOnly = true
+		case annotations.FieldBehavior_INPUT_ONLY:
+			fieldData.IsInputOnly = true
+		case annotations.FieldBehavior_IMMUTABLE:
+			fieldData.IsImmutable = true
+		default:
+		}
+	}
+}
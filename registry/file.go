@@ -20,26 +20,43 @@ func (r *Registry) analyseFile(f *descriptorpb.FileDescriptorProto) (*data.File,
 	packageName := f.GetPackage()
 	parents := make([]string, 0)
 	fileData.Name = fileName
-	fileData.TSFileName = data.GetTSFileName(fileName)
+
+	r.analyseComments(f)
+	r.analyseSourceLocations(f)
+	r.analyseFileOptions(f)
+	fileData.TSFileName = r.getTSFileName(fileName)
 	if proto.HasExtension(f.Options, options.E_TsPackage) {
 		r.TSPackages[fileData.TSFileName], _ = proto.GetExtension(f.Options, options.E_TsPackage).(string)
 	}
+	if r.GenerateResourceNames {
+		r.analyseFileResources(fileData, packageName, fileName, f.GetOptions())
+	}
 
 	// analyse enums
-	for _, enum := range f.EnumType {
-		r.analyseEnumType(fileData, packageName, fileName, parents, enum)
+	for idx, enum := range f.EnumType {
+		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+		loc := sourceLocation{file: fileName, path: []int32{fileEnumTypeField, int32(idx)}}
+		r.analyseEnumType(fileData, packageName, fileName, parents, loc, enum)
 	}
 
 	// analyse messages, each message will go recursively
-	for _, message := range f.MessageType {
-		r.analyseMessage(fileData, packageName, fileName, parents, message)
+	for idx, message := range f.MessageType {
+		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+		loc := sourceLocation{file: fileName, path: []int32{fileMessageField, int32(idx)}}
+		if err := r.analyseMessage(fileData, packageName, fileName, parents, loc, message); err != nil {
+			return nil, errors.Wrapf(err, "error analysing messages of file %s", fileName)
+		}
 	}
 
 	// analyse services
-	for _, service := range f.Service {
-		r.analyseService(fileData, packageName, fileName, service)
+	for idx, service := range f.Service {
+		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+		loc := sourceLocation{file: fileName, path: []int32{fileServiceField, int32(idx)}}
+		r.analyseService(fileData, packageName, fileName, loc, service)
 	}
 
+	r.addTypeImportDependencies(fileData)
+
 	// add fetch module after analysed all services in the file. will add dependencies if there is any
 	err := r.addFetchModuleDependencies(fileData)
 	if err != nil {
@@ -52,7 +69,7 @@ func (r *Registry) analyseFile(f *descriptorpb.FileDescriptorProto) (*data.File,
 }
 
 func (r *Registry) addFetchModuleDependencies(fileData *data.File) error {
-	if !fileData.Services.RequiresFetchModule() {
+	if !r.RequiresFetchModule(fileData) {
 		slog.Debug("no services found for, skipping fetch module", slog.String("name", fileData.Name))
 		return nil
 	}
@@ -0,0 +1,99 @@
+package registry
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/api/visibility"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// filterSeparator separates the patterns of include and exclude, and the labels of visibility_labels.
+const filterSeparator = ";"
+
+// parseFilterPatterns splits a list of glob patterns and checks their syntax.
+func parseFilterPatterns(name, value string) ([]string, error) {
+	patterns := make([]string, 0)
+	for _, pattern := range strings.Split(value, filterSeparator) {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), ".")
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s pattern %q", name, pattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// parseVisibilityLabels returns the set of visibility labels, or nil when the parameter is not set and
+// visibility restrictions are ignored.
+func parseVisibilityLabels(value string) map[string]bool {
+	if value == "" {
+		return nil
+	}
+	labels := make(map[string]bool)
+	for _, label := range strings.Split(value, filterSeparator) {
+		if label = strings.TrimSpace(label); label != "" {
+			labels[label] = true
+		}
+	}
+	return labels
+}
+
+// matchesAny returns whether one of the names matches one of the patterns.
+func matchesAny(patterns []string, names ...string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isMethodIncluded returns whether a method is generated according to the include and exclude
+// patterns, which match the fully qualified names of services and methods, and to the
+// google.api.visibility restrictions of the service and the method. Exclusions win over inclusions,
+// and methods with ts_method.skip are always left out.
+func (r *Registry) isMethodIncluded(
+	fqServiceName, fqMethodName string,
+	service *descriptorpb.ServiceDescriptorProto,
+	method *descriptorpb.MethodDescriptorProto) bool {
+	if methodOptions(method).GetSkip() {
+		return false
+	}
+	serviceName := strings.TrimPrefix(fqServiceName, ".")
+	methodName := strings.TrimPrefix(fqMethodName, ".")
+	if len(r.includes) > 0 && !matchesAny(r.includes, serviceName, methodName) {
+		return false
+	}
+	if matchesAny(r.excludes, serviceName, methodName) {
+		return false
+	}
+	if r.visibilityLabels == nil {
+		return true
+	}
+	serviceRule, _ := proto.GetExtension(service.GetOptions(), visibility.E_ApiVisibility).(*visibility.VisibilityRule)
+	methodRule, _ := proto.GetExtension(method.GetOptions(), visibility.E_MethodVisibility).(*visibility.VisibilityRule)
+	return r.isVisible(serviceRule) && r.isVisible(methodRule)
+}
+
+// isVisible returns whether an element with the given visibility rule is visible with the labels of
+// visibility_labels, elements without restriction are always visible.
+func (r *Registry) isVisible(rule *visibility.VisibilityRule) bool {
+	restriction := strings.TrimSpace(rule.GetRestriction())
+	if restriction == "" {
+		return true
+	}
+	for _, label := range strings.Split(restriction, ",") {
+		if r.visibilityLabels[strings.TrimSpace(label)] {
+			return true
+		}
+	}
+	return false
+}
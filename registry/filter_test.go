@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filteredFile = `
+name: "library.proto"
+package: "library"
+message_type { name: "Book" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+message_type { name: "DeleteBookRequest" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+message_type { name: "Shelf" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+message_type { name: "Unused" }
+service {
+  name: "Library"
+  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+    options { [google.api.http] { get: "/v1/books/{name}" } } }
+  method { name: "DeleteBook" input_type: ".library.DeleteBookRequest" output_type: ".library.Book"
+    options { [google.api.http] { delete: "/v1/books/{name}" } [google.api.method_visibility] { restriction: "PREVIEW" } } }
+}
+service {
+  name: "Shelves"
+  options { [google.api.api_visibility] { restriction: "INTERNAL, PREVIEW" } }
+  method { name: "GetShelf" input_type: ".library.Shelf" output_type: ".library.Shelf"
+    options { [google.api.http] { get: "/v1/shelves/{name}" } } }
+  method { name: "GetShelfBook" input_type: ".library.Book" output_type: ".library.Book"
+    options { [google.api.http] { get: "/v1/shelves/{name}/book" } } }
+}
+`
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		names    []string
+		want     bool
+	}{
+		{name: "no patterns", names: []string{"library.Library"}, want: false},
+		{name: "exact", patterns: []string{"library.Library"}, names: []string{"library.Library"}, want: true},
+		{name: "wildcard", patterns: []string{"library.*"}, names: []string{"library.Library"}, want: true},
+		{name: "wildcard within a name", patterns: []string{"library.Get*"}, names: []string{"library.GetBook"}, want: true},
+		{
+			name:     "second name",
+			patterns: []string{"library.Library.Get*"},
+			names:    []string{"library.Library", "library.Library.GetBook"},
+			want:     true,
+		},
+		{
+			name:     "second pattern",
+			patterns: []string{"other.*", "library.Library"},
+			names:    []string{"library.Library", "library.Library.GetBook"},
+			want:     true,
+		},
+		{name: "no match", patterns: []string{"library.Shelves"}, names: []string{"library.Library"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesAny(tt.patterns, tt.names...))
+		})
+	}
+}
+
+func TestIsMethodIncluded(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "no filters",
+			want: []string{"Library.DeleteBook", "Library.GetBook", "Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "include service",
+			opts: Options{Include: "library.Shelves"},
+			want: []string{"Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "include service and method",
+			opts: Options{Include: "library.Shelves; library.Library.GetBook"},
+			want: []string{"Library.GetBook", "Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "include methods with a leading dot",
+			opts: Options{Include: ".library.*.Get*"},
+			want: []string{"Library.GetBook", "Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "exclude method",
+			opts: Options{Exclude: "library.Library.DeleteBook"},
+			want: []string{"Library.GetBook", "Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "exclude wins over the service",
+			opts: Options{Include: "library.Library", Exclude: "library.Library.DeleteBook"},
+			want: []string{"Library.GetBook"},
+		},
+		{
+			name: "exclude service wins over the method",
+			opts: Options{Include: "library.Shelves.GetShelf", Exclude: "library.Shelves"},
+			want: []string{},
+		},
+		{
+			name: "no visibility labels",
+			opts: Options{VisibilityLabels: "PUBLIC"},
+			want: []string{"Library.GetBook"},
+		},
+		{
+			name: "visibility label of the method",
+			opts: Options{VisibilityLabels: "PREVIEW"},
+			want: []string{"Library.DeleteBook", "Library.GetBook", "Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "visibility label of the service",
+			opts: Options{VisibilityLabels: "INTERNAL"},
+			want: []string{"Library.GetBook", "Shelves.GetShelf", "Shelves.GetShelfBook"},
+		},
+		{
+			name: "visibility labels and exclude",
+			opts: Options{VisibilityLabels: "INTERNAL;PREVIEW", Exclude: "library.Shelves.GetShelfBook"},
+			want: []string{"Library.DeleteBook", "Library.GetBook", "Shelves.GetShelf"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, filesData, err := analyse(t, tt.opts, newRequest(t, filteredFile))
+			require.NoError(t, err)
+			methods := make([]string, 0)
+			for _, service := range filesData["library.proto"].Services {
+				for _, method := range service.Methods {
+					methods = append(methods, service.Name+"."+method.Name)
+				}
+			}
+			sort.Strings(methods)
+			assert.Equal(t, tt.want, methods)
+		})
+	}
+}
+
+func TestInvalidFilterPattern(t *testing.T) {
+	_, err := NewRegistry(Options{Exclude: "library.[Library"})
+	assert.ErrorContains(t, err, `invalid exclude pattern "library.[Library"`)
+}
+
+func TestPruneFilteredTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "no filters",
+			want: []string{".library.Book", ".library.DeleteBookRequest", ".library.Shelf", ".library.Unused"},
+		},
+		{
+			// Book is still used by GetBook, Unused isn't used by any method
+			name: "exclude method",
+			opts: Options{Exclude: "library.Library.DeleteBook"},
+			want: []string{".library.Book", ".library.Shelf", ".library.Unused"},
+		},
+		{
+			name: "exclude service",
+			opts: Options{Exclude: "library.Shelves"},
+			want: []string{".library.Book", ".library.DeleteBookRequest", ".library.Unused"},
+		},
+		{
+			name: "include method",
+			opts: Options{Include: "library.Shelves.GetShelf"},
+			want: []string{".library.Shelf", ".library.Unused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, filesData, err := analyse(t, tt.opts, newRequest(t, filteredFile))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, typeNames(filesData["library.proto"]))
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Numbers of the fields leading to the HTTP rules of a method in the paths of the source code info.
+const (
+	fileServiceField        = 6
+	serviceMethodField      = 2
+	methodOptionsField      = 4
+	httpExtensionField      = 72295728
+	httpRuleGetField        = 2
+	httpRulePutField        = 3
+	httpRulePostField       = 4
+	httpRuleDeleteField     = 5
+	httpRulePatchField      = 6
+	httpRuleBodyField       = 7
+	httpRuleCustomField     = 8
+	httpRuleAdditionalField = 11
+	httpRuleResponseField   = 12
+)
+
+// pathVariableRegexp matches the {field} and {field=pattern} variables of path templates.
+var pathVariableRegexp = regexp.MustCompile(`{([^=}/]+)(?:=([^}]+))?}`)
+
+// httpBinding is an HTTP rule of a method along with its location.
+type httpBinding struct {
+	rule *annotations.HttpRule
+	loc  sourceLocation
+}
+
+// getHTTPBindings returns the HTTP rules of a method, the google.api.http annotation comes first and
+// takes precedence over the rules of the gRPC API configuration, which are served as well by the
+// gateway. The additional bindings of every rule follow the rule.
+func (r *Registry) getHTTPBindings(
+	fqMethodName string, m *descriptorpb.MethodDescriptorProto, loc sourceLocation) []*httpBinding {
+	rules := make([]*httpBinding, 0)
+	if rule := getHTTPAnnotation(m); rule != nil {
+		rules = append(rules, &httpBinding{rule: rule, loc: loc.child(methodOptionsField, httpExtensionField)})
+	}
+	rules = append(rules, r.httpRules[fqMethodName]...)
+
+	bindings := make([]*httpBinding, 0, len(rules))
+	for _, binding := range rules {
+		bindings = append(bindings, binding)
+		for idx, rule := range binding.rule.GetAdditionalBindings() {
+			bindings = append(bindings, &httpBinding{
+				rule: rule,
+				loc:  binding.loc.child(httpRuleAdditionalField, int32(idx)), //nolint:gosec // G115: small index
+			})
+		}
+	}
+	return bindings
+}
+
+// validateHTTPBinding reports the problems of an HTTP rule: path variables which don't name a field
+// of the request that can be set from a path, bodies which don't name a top-level field that isn't
+// repeated, GET methods with a body, and paths already bound to a method of another service. Paths
+// bound twice in a service and request fields which can't be sent as query parameters are reported
+// as warnings.
+func (r *Registry) validateHTTPBinding(
+	binding *httpBinding, methodName string, method *descriptorpb.MethodDescriptorProto) {
+	rule := binding.rule
+	httpMethod, url, patternField := httpRulePattern(rule)
+	patternLoc := binding.loc.child(patternField)
+	if httpMethod == "" {
+		r.report(SeverityError, patternLoc, "%s: the HTTP rule has no method", methodName)
+		return
+	}
+	if url == "" || !strings.HasPrefix(url, "/") {
+		r.report(SeverityError, patternLoc, "%s: path %q of the HTTP rule must start with /", methodName, url)
+		return
+	}
+
+	routeKey := httpMethod + " " + pathVariableRegexp.ReplaceAllStringFunc(url, func(m string) string {
+		if pattern := pathVariableRegexp.FindStringSubmatch(m)[2]; pattern != "" {
+			return pattern
+		}
+		return "*"
+	})
+	if other, ok := r.routes[routeKey]; ok {
+		// the gateway serves the route with the method registered last, which is only reliable within a
+		// service
+		severity := SeverityError
+		if methodService(other) == methodService(methodName) {
+			severity = SeverityWarning
+		}
+		r.report(severity, patternLoc, "%s: %s %s is already bound to %s", methodName, httpMethod, url, other)
+	} else {
+		r.routes[routeKey] = methodName
+	}
+
+	input, ok := r.Types[method.GetInputType()]
+	if !ok || input.Message == nil {
+		return
+	}
+
+	boundFields := make(map[string]bool)
+	for _, m := range pathVariableRegexp.FindAllStringSubmatch(url, -1) {
+		fieldPath := strings.TrimSpace(m[1])
+		boundFields[strings.Split(fieldPath, ".")[0]] = true
+		field, problem := r.lookupFieldPath(input, fieldPath)
+		switch {
+		case problem != "":
+			r.report(SeverityError, patternLoc, "%s: path variable %s: %s", methodName, fieldPath, problem)
+		case !r.isPathField(field):
+			r.report(SeverityError, patternLoc,
+				"%s: path variable %s must be a field holding a single scalar, enum or well-known type", methodName, fieldPath)
+		}
+	}
+
+	body := rule.GetBody()
+	bodyLoc := binding.loc.child(httpRuleBodyField)
+	switch {
+	case body != "" && httpMethod == "GET":
+		r.report(SeverityError, bodyLoc, "%s: GET methods can't have a body", methodName)
+	case body != "" && body != "*":
+		boundFields[body] = true
+		field := messageField(input.Message, body)
+		if field == nil {
+			r.report(SeverityError, bodyLoc, "%s: body %s is not a field of %s", methodName, body,
+				strings.TrimPrefix(input.FullyQualifiedName, "."))
+		} else if field.IsRepeated {
+			r.report(SeverityError, bodyLoc, "%s: body %s must not be a repeated or map field", methodName, body)
+		}
+	}
+
+	if responseBody := rule.GetResponseBody(); responseBody != "" {
+		output, ok := r.Types[method.GetOutputType()]
+		if ok && output.Message != nil && messageField(output.Message, responseBody) == nil {
+			r.report(SeverityError, binding.loc.child(httpRuleResponseField), "%s: response body %s is not a field of %s",
+				methodName, responseBody, strings.TrimPrefix(output.FullyQualifiedName, "."))
+		}
+	}
+
+	if body == "*" {
+		return
+	}
+	for _, f := range input.Message.Fields {
+		if !boundFields[f.Name] {
+			r.validateQueryField(patternLoc, methodName, f.Name, f, make(map[string]bool))
+		}
+	}
+}
+
+// validateQueryField reports the fields which the gateway can't read from query parameters, that is
+// maps and repeated messages, nested messages are walked through.
+func (r *Registry) validateQueryField(
+	loc sourceLocation, methodName, fieldPath string, field *data.Field, visited map[string]bool) {
+	typeInfo, ok := r.Types[field.Type]
+	if !ok || typeInfo.Message == nil && !typeInfo.IsMapEntry || r.isPathField(&data.Field{Type: field.Type}) {
+		return
+	}
+	if typeInfo.IsMapEntry || field.IsRepeated {
+		r.report(SeverityWarning, loc, "%s: field %s of the request can't be sent as a query parameter", methodName, fieldPath)
+		return
+	}
+	if visited[field.Type] {
+		return
+	}
+	visited[field.Type] = true
+	defer delete(visited, field.Type)
+	for _, f := range typeInfo.Message.Fields {
+		r.validateQueryField(loc, methodName, fieldPath+"."+f.Name, f, visited)
+	}
+}
+
+// lookupFieldPath returns the field a dotted path leads to from a message, or the reason why the path
+// doesn't lead to a field.
+func (r *Registry) lookupFieldPath(msg *TypeInformation, fieldPath string) (*data.Field, string) {
+	parts := strings.Split(fieldPath, ".")
+	for i, name := range parts {
+		field := messageField(msg.Message, name)
+		if field == nil {
+			return nil, "no field " + name + " in " + strings.TrimPrefix(msg.FullyQualifiedName, ".")
+		}
+		if i == len(parts)-1 {
+			return field, ""
+		}
+		next, ok := r.Types[field.Type]
+		if field.IsRepeated || !ok || next.Message == nil {
+			return nil, strings.Join(parts[:i+1], ".") + " is not a singular message field"
+		}
+		msg = next
+	}
+	return nil, "empty field path"
+}
+
+// isPathField returns whether a field can be set from a path variable.
+func (r *Registry) isPathField(field *data.Field) bool {
+	if field.IsRepeated {
+		return false
+	}
+	switch field.Type {
+	case ".google.protobuf.Timestamp", ".google.protobuf.Duration", ".google.protobuf.FieldMask":
+		return true
+	}
+	if isWrapperType(field.Type) || !strings.HasPrefix(field.Type, ".") {
+		return true
+	}
+	typeInfo, ok := r.Types[field.Type]
+	return ok && typeInfo.EnumValues != nil
+}
+
+func isWrapperType(protoType string) bool {
+	switch protoType {
+	case ".google.protobuf.BoolValue", ".google.protobuf.StringValue", ".google.protobuf.BytesValue",
+		".google.protobuf.DoubleValue", ".google.protobuf.FloatValue",
+		".google.protobuf.Int32Value", ".google.protobuf.Int64Value",
+		".google.protobuf.UInt32Value", ".google.protobuf.UInt64Value":
+		return true
+	}
+	return false
+}
+
+// methodService returns the name of the service of a method.
+func methodService(methodName string) string {
+	return methodName[:strings.LastIndex(methodName, ".")]
+}
+
+// messageField returns the field of a message with the given proto name, or nil.
+func messageField(msg *data.Message, name string) *data.Field {
+	for _, f := range msg.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// httpRulePattern returns the HTTP method and the path of a rule, along with the number of the field
+// holding them, the method is empty when the rule has no pattern.
+func httpRulePattern(rule *annotations.HttpRule) (string, string, int32) {
+	switch rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return "GET", rule.GetGet(), httpRuleGetField
+	case *annotations.HttpRule_Put:
+		return "PUT", rule.GetPut(), httpRulePutField
+	case *annotations.HttpRule_Post:
+		return "POST", rule.GetPost(), httpRulePostField
+	case *annotations.HttpRule_Delete:
+		return "DELETE", rule.GetDelete(), httpRuleDeleteField
+	case *annotations.HttpRule_Patch:
+		return "PATCH", rule.GetPatch(), httpRulePatchField
+	default:
+		return rule.GetCustom().GetKind(), rule.GetCustom().GetPath(), httpRuleCustomField
+	}
+}
+
+// child returns the location of a child element.
+func (l sourceLocation) child(path ...int32) sourceLocation {
+	if l.path == nil {
+		return l
+	}
+	childPath := make([]int32, 0, len(l.path)+len(path))
+	childPath = append(childPath, l.path...)
+	return sourceLocation{file: l.file, path: append(childPath, path...)}
+}
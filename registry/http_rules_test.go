@@ -0,0 +1,179 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const libraryMessages = `
+name: "library.proto"
+package: "library"
+message_type {
+  name: "Author"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+}
+message_type {
+  name: "Book"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+  field { name: "tags" number: 2 type: TYPE_STRING label: LABEL_REPEATED }
+  field { name: "author" number: 3 type: TYPE_MESSAGE type_name: ".library.Author" label: LABEL_OPTIONAL }
+}
+message_type {
+  name: "UpdateBookRequest"
+  field { name: "book" number: 1 type: TYPE_MESSAGE type_name: ".library.Book" label: LABEL_OPTIONAL }
+  field { name: "books" number: 2 type: TYPE_MESSAGE type_name: ".library.Book" label: LABEL_REPEATED }
+}
+message_type {
+  name: "ListBooksRequest"
+  field { name: "filter" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+  field { name: "author" number: 2 type: TYPE_MESSAGE type_name: ".library.Author" label: LABEL_OPTIONAL }
+  field { name: "authors" number: 3 type: TYPE_MESSAGE type_name: ".library.Author" label: LABEL_REPEATED }
+  field { name: "labels" number: 4 type: TYPE_MESSAGE type_name: ".library.ListBooksRequest.LabelsEntry" label: LABEL_REPEATED }
+  nested_type {
+    name: "LabelsEntry"
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+    field { name: "value" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL }
+    options { map_entry: true }
+  }
+}
+`
+
+func TestValidateHTTPBinding(t *testing.T) {
+	tests := []struct {
+		name         string
+		services     string
+		wantErrors   []string
+		wantWarnings []string
+	}{
+		{
+			name: "valid rules",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{name=books/*}" response_body: "author" } } }
+			  method { name: "UpdateBook" input_type: ".library.UpdateBookRequest" output_type: ".library.Book"
+			    options { [google.api.http] { patch: "/v1/{book.name=books/*}" body: "*" } } }
+			}`,
+		},
+		{
+			name: "missing path field",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{title}" } } }
+			}`,
+			wantErrors: []string{"library.Library.GetBook: path variable title: no field title in library.Book"},
+		},
+		{
+			name: "nested path variable",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/authors/{author.id}/books" } } }
+			}`,
+		},
+		{
+			name: "nested path variable through a scalar",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{name.id}" } } }
+			}`,
+			wantErrors: []string{"library.Library.GetBook: path variable name.id: name is not a singular message field"},
+		},
+		{
+			name: "repeated path field",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{tags}" } } }
+			}`,
+			wantErrors: []string{
+				"library.Library.GetBook: path variable tags must be a field holding a single scalar, enum or well-known type",
+			},
+		},
+		{
+			name: "missing body field",
+			services: `service { name: "Library"
+			  method { name: "UpdateBook" input_type: ".library.UpdateBookRequest" output_type: ".library.Book"
+			    options { [google.api.http] { patch: "/v1/books" body: "title" } } }
+			}`,
+			wantErrors: []string{"library.Library.UpdateBook: body title is not a field of library.UpdateBookRequest"},
+			wantWarnings: []string{
+				"library.Library.UpdateBook: field books of the request can't be sent as a query parameter",
+			},
+		},
+		{
+			name: "repeated body field",
+			services: `service { name: "Library"
+			  method { name: "UpdateBook" input_type: ".library.UpdateBookRequest" output_type: ".library.Book"
+			    options { [google.api.http] { patch: "/v1/books" body: "books" } } }
+			}`,
+			wantErrors: []string{"library.Library.UpdateBook: body books must not be a repeated or map field"},
+		},
+		{
+			name: "GET with a body",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{name}" body: "*" } } }
+			}`,
+			wantErrors: []string{"library.Library.GetBook: GET methods can't have a body"},
+		},
+		{
+			// unlike GET, the gateway reads the body of DELETE requests
+			name: "DELETE with a body",
+			services: `service { name: "Library"
+			  method { name: "DeleteBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { delete: "/v1/{name}" body: "*" } } }
+			}`,
+		},
+		{
+			name: "missing response body field",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{name}" response_body: "title" } } }
+			}`,
+			wantErrors: []string{"library.Library.GetBook: response body title is not a field of library.Book"},
+		},
+		{
+			name: "route bound in two services",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{name}" } } }
+			}
+			service { name: "Shelves"
+			  method { name: "GetAuthorBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{author.id}" } } }
+			}`,
+			wantErrors: []string{
+				"library.Shelves.GetAuthorBook: GET /v1/{author.id} is already bound to library.Library.GetBook",
+			},
+		},
+		{
+			name: "route bound twice in a service",
+			services: `service { name: "Library"
+			  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{name}" } } }
+			  method { name: "GetAuthorBook" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/{author.id}" } } }
+			}`,
+			wantWarnings: []string{
+				"library.Library.GetAuthorBook: GET /v1/{author.id} is already bound to library.Library.GetBook",
+			},
+		},
+		{
+			name: "query parameters of message fields",
+			services: `service { name: "Library"
+			  method { name: "ListBooks" input_type: ".library.ListBooksRequest" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/books" } } }
+			}`,
+			wantWarnings: []string{
+				"library.Library.ListBooks: field authors of the request can't be sent as a query parameter",
+				"library.Library.ListBooks: field labels of the request can't be sent as a query parameter",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, err := analyse(t, Options{}, newRequest(t, libraryMessages+tt.services))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Equal(t, tt.wantWarnings, warningMessages(r))
+		})
+	}
+}
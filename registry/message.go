@@ -1,7 +1,10 @@
 package registry
 
 import (
+	"strings"
+
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/options"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -9,8 +12,20 @@ func (r *Registry) analyseMessage(
 	fileData *data.File,
 	packageName, fileName string,
 	parents []string,
-	message *descriptorpb.DescriptorProto) {
+	loc sourceLocation,
+	message *descriptorpb.DescriptorProto) error {
 	packageIdentifier := r.getNameOfPackageLevelIdentifier(parents, message.GetName())
+	opts := messageOptions(message)
+	optionsLoc := loc.child(messageOptionsField, tsMessageExtensionField)
+	// nameLoc locates the name set with ts_message, which is never renamed
+	var nameLoc *sourceLocation
+	if name := opts.GetName(); name != "" {
+		optionLoc := optionsLoc.child(tsMessageNameField)
+		if r.validIdentifier(fileName, optionLoc, "name", name) {
+			packageIdentifier = name
+			nameLoc = &optionLoc
+		}
+	}
 
 	fqName := r.getFullQualifiedName(packageName, parents, message.GetName())
 	protoType := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
@@ -22,6 +37,7 @@ func (r *Registry) analyseMessage(
 		PackageIdentifier:  packageIdentifier,
 		LocalIdentifier:    message.GetName(),
 		ProtoType:          protoType,
+		IsSkipped:          opts.GetSkip(),
 	}
 
 	// register itself in the registry map
@@ -50,7 +66,7 @@ func (r *Registry) analyseMessage(
 			fileData.TrackPackageNonScalarType(typeInfo.KeyType)
 			fileData.TrackPackageNonScalarType(typeInfo.ValueType)
 			// no need to add a map type into
-			return
+			return nil
 		}
 	}
 
@@ -58,31 +74,66 @@ func (r *Registry) analyseMessage(
 	data.Name = packageIdentifier
 	data.FQType = fqName
 	data.IsDeprecated = message.GetOptions().GetDeprecated()
+	data.IsValidationDisabled = r.isValidationDisabled(message.GetOptions())
+	data.IsReadonly = opts.GetReadonly()
+	typeInfo.Message = data
+	if !typeInfo.IsSkipped {
+		r.declareMessage(fileName, parents, typeInfo, data, nameLoc)
+	}
 
 	newParents := []string{}
 	newParents = append(newParents, parents...)
 	newParents = append(newParents, message.GetName())
 
 	// handle enums, by pulling the enums out to the top level
-	for _, enum := range message.EnumType {
-		r.analyseEnumType(fileData, packageName, fileName, newParents, enum)
+	for idx, enum := range message.EnumType {
+		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+		r.analyseEnumType(fileData, packageName, fileName, newParents, loc.child(messageEnumTypeField, int32(idx)), enum)
 	}
 
 	// nested type also got pull out to the top level of the file
-	for _, msg := range message.NestedType {
-		r.analyseMessage(fileData, packageName, fileName, newParents, msg)
+	for idx, msg := range message.NestedType {
+		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+		nestedLoc := loc.child(messageNestedField, int32(idx))
+		if err := r.analyseMessage(fileData, packageName, fileName, newParents, nestedLoc, msg); err != nil {
+			return err
+		}
 	}
 
 	// store a map of one of names
 	for idx, oneOf := range message.GetOneofDecl() {
 		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
 		data.OneOfFieldsNames[int32(idx)] = oneOf.GetName()
+		if r.isOneOfRequired(oneOf.GetOptions()) {
+			//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+			data.RequiredOneOfs[int32(idx)] = true
+		}
 	}
 
 	// analyse fields in the messages
-	for _, f := range message.Field {
-		r.analyseField(fileData, data, packageName, f)
+	for idx, f := range message.Field {
+		//nolint:gosec // G115: idx from range is safe to convert to int32 for protobuf field indices
+		r.analyseField(fileData, data, packageName, fileName, loc.child(messageFieldField, int32(idx)), f)
+	}
+
+	if r.GenerateResourceNames {
+		r.analyseMessageResource(fileData, packageName, fileName, data, message.GetOptions())
+	}
+
+	if opts.GetDeclaration() == options.Declaration_DECLARATION_INTERFACE {
+		if data.HasOneOfFields() {
+			if r.IsFileToGenerate(fileName) {
+				r.report(SeverityError, optionsLoc.child(tsMessageDeclarationField),
+					"%s: messages with oneofs can't be declared as interfaces", strings.TrimPrefix(fqName, "."))
+			}
+		} else {
+			data.IsInterface = true
+		}
 	}
 
+	if typeInfo.IsSkipped {
+		return nil
+	}
 	fileData.Messages = append(fileData.Messages, data)
+	return nil
 }
@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// operationType is the type returned by long-running methods.
+	operationType = ".google.longrunning.Operation"
+	// operationInfoExtension is the name of the extension of MethodOptions declaring the types of an
+	// operation. It is read through the descriptors of the request, see extension.
+	operationInfoExtension = "google.longrunning.operation_info"
+)
+
+// analyseOperation returns the types of the response and metadata of a method returning a
+// google.longrunning.Operation, as declared with google.longrunning.operation_info, or nil when the
+// method has no such annotation.
+func (r *Registry) analyseOperation(
+	fileData *data.File, packageName string, method *descriptorpb.MethodDescriptorProto) *data.Operation {
+	if method.GetOutputType() != operationType {
+		return nil
+	}
+	responseType, metadataType, ok := r.readOperationInfo(method.GetOptions())
+	if !ok {
+		return nil
+	}
+	return &data.Operation{
+		ResponseType: r.operationArgument(fileData, packageName, responseType),
+		MetadataType: r.operationArgument(fileData, packageName, metadataType),
+	}
+}
+
+// operationArgument resolves the name of a type used in operation_info, which is either fully
+// qualified or relative to the package of the method, and tracks it as a dependency of the file.
+// It returns nil when the type can't be found.
+func (r *Registry) operationArgument(fileData *data.File, packageName, name string) *data.MethodArgument {
+	if name == "" {
+		return nil
+	}
+	fqName := "." + strings.TrimPrefix(name, ".")
+	if _, ok := r.Types[fqName]; !ok {
+		fqName = "." + packageName + "." + name
+		if _, ok := r.Types[fqName]; !ok {
+			return nil
+		}
+	}
+	arg := &data.MethodArgument{
+		Type:       fqName,
+		IsExternal: r.isExternalDependenciesOutsidePackage(fqName, packageName),
+	}
+	if arg.IsExternal {
+		fileData.ExternalDependingTypes = append(fileData.ExternalDependingTypes, fqName)
+	}
+	fileData.TrackPackageNonScalarType(arg)
+	return arg
+}
+
+// readOperationInfo reads the response_type and metadata_type of the google.longrunning.operation_info
+// extension of the options of a method.
+func (r *Registry) readOperationInfo(opts *descriptorpb.MethodOptions) (string, string, bool) {
+	if opts == nil {
+		return "", "", false
+	}
+	info, ok := r.extension(opts, operationInfoExtension)
+	if !ok {
+		return "", "", false
+	}
+	return stringField(info.Message(), "response_type"), stringField(info.Message(), "metadata_type"), true
+}
+
+// stringField returns the value of a string field of a message, "" when the message has no such field.
+func stringField(msg protoreflect.Message, name protoreflect.Name) string {
+	fd := msg.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != protoreflect.StringKind {
+		return ""
+	}
+	return msg.Get(fd).String()
+}
@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// operationsFile declares the parts of google/longrunning/operations.proto read by the tests.
+const operationsFile = `name: "google/longrunning/operations.proto" package: "google.longrunning"
+  dependency: "google/protobuf/descriptor.proto"
+  message_type { name: "Operation" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+  message_type { name: "OperationInfo"
+    field { name: "response_type" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+    field { name: "metadata_type" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL } }
+  extension { name: "operation_info" number: 1049 type: TYPE_MESSAGE type_name: ".google.longrunning.OperationInfo"
+    extendee: ".google.protobuf.MethodOptions" label: LABEL_OPTIONAL }`
+
+const operationService = `name: "library.proto" package: "library"
+  dependency: "google/longrunning/operations.proto"
+  message_type { name: "Book" }
+  message_type { name: "CreateBookMetadata" }
+  service { name: "Library"
+    method { name: "CreateBook" input_type: ".library.Book" output_type: ".google.longrunning.Operation"
+      options { [google.api.http] { post: "/v1/books" body: "*" } } } }`
+
+func TestOperationInfo(t *testing.T) {
+	info := appendMessage(nil, 1049,
+		protowire.AppendString(protowire.AppendTag(
+			protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), ".library.Book"),
+			2, protowire.BytesType), "CreateBookMetadata"))
+	tests := []struct {
+		name         string
+		setInfo      func(t *testing.T, r *Registry, opts *descriptorpb.MethodOptions)
+		wantResponse string
+		wantMetadata string
+	}{
+		{
+			// the plugin doesn't link the longrunning package, so protoc's encoding is left unknown
+			name: "unknown fields",
+			setInfo: func(_ *testing.T, _ *Registry, opts *descriptorpb.MethodOptions) {
+				opts.ProtoReflect().SetUnknown(append(opts.ProtoReflect().GetUnknown(), info...))
+			},
+			wantResponse: ".library.Book",
+			wantMetadata: ".library.CreateBookMetadata",
+		},
+		{
+			// the options are decoded along with the request when the longrunning package is linked
+			name: "decoded extension",
+			setInfo: func(t *testing.T, r *Registry, opts *descriptorpb.MethodOptions) {
+				xt, err := r.extensionTypes.FindExtensionByName(operationInfoExtension)
+				require.NoError(t, err)
+				value := dynamicpb.NewMessage(xt.TypeDescriptor().Message())
+				value.Set(value.Descriptor().Fields().ByName("response_type"), protoreflect.ValueOfString("Book"))
+				opts.ProtoReflect().Set(xt.TypeDescriptor(), protoreflect.ValueOfMessage(value))
+			},
+			wantResponse: ".library.Book",
+		},
+		{
+			name:    "no annotation",
+			setInfo: func(*testing.T, *Registry, *descriptorpb.MethodOptions) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(t, operationsFile, operationService)
+			descriptor := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+			req.ProtoFile = append([]*descriptorpb.FileDescriptorProto{descriptor}, req.ProtoFile...)
+			req.FileToGenerate = []string{"library.proto"}
+			r, err := NewRegistry(Options{GenerateOperationHelpers: true})
+			require.NoError(t, err)
+			require.NoError(t, r.analyseExtensionTypes(req))
+			tt.setInfo(t, r, req.ProtoFile[len(req.ProtoFile)-1].Service[0].Method[0].Options)
+
+			filesData, err := r.Analyse(req)
+			require.NoError(t, err)
+			operation := filesData["library.proto"].Services[0].Methods[0].Output.Operation
+			if tt.wantResponse == "" {
+				assert.Nil(t, operation)
+				return
+			}
+			require.NotNil(t, operation)
+			assert.Equal(t, tt.wantResponse, operation.ResponseType.Type)
+			if tt.wantMetadata == "" {
+				assert.Nil(t, operation.MetadataType)
+			} else {
+				assert.Equal(t, tt.wantMetadata, operation.MetadataType.Type)
+			}
+		})
+	}
+}
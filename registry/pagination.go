@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Names of the fields of the requests and responses of List methods, see https://google.aip.dev/158.
+const (
+	pageSizeField      = "page_size"
+	pageTokenField     = "page_token"
+	nextPageTokenField = "next_page_token"
+)
+
+// analysePagination returns the pagination of a method whose request and response follow AIP-158,
+// or nil otherwise. The request and response are looked up in the registry, so they can be declared
+// in imported files.
+func (r *Registry) analysePagination(method *descriptorpb.MethodDescriptorProto) *data.Pagination {
+	if method.GetServerStreaming() || method.GetClientStreaming() {
+		return nil
+	}
+	request := r.messageOf(method.GetInputType())
+	response := r.messageOf(method.GetOutputType())
+	if request == nil || response == nil {
+		return nil
+	}
+	if !hasField(request, pageSizeField, "int32") || !hasField(request, pageTokenField, "string") ||
+		!hasField(response, nextPageTokenField, "string") {
+		return nil
+	}
+	// the items are the first repeated field of the response
+	for _, f := range response.Fields {
+		if !f.IsRepeated {
+			continue
+		}
+		if typeInfo, ok := r.Types[f.Type]; ok && typeInfo.IsMapEntry {
+			continue
+		}
+		return &data.Pagination{
+			PageTokenField:     pageTokenField,
+			NextPageTokenField: nextPageTokenField,
+			ItemsField:         f.Name,
+		}
+	}
+	return nil
+}
+
+func (r *Registry) messageOf(fqName string) *data.Message {
+	if typeInfo, ok := r.Types[fqName]; ok {
+		return typeInfo.Message
+	}
+	return nil
+}
+
+func hasField(msg *data.Message, name, fieldType string) bool {
+	for _, f := range msg.Fields {
+		if f.Name == name && f.Type == fieldType && !f.IsRepeated {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paginatedFile declares a ListBooks method taking the fields of ListBooksRequest and returning the
+// fields of ListBooksResponse.
+const paginatedFile = `name: "library.proto" package: "library"
+  message_type { name: "Book" }
+  message_type { name: "ListBooksRequest" %s }
+  message_type { name: "ListBooksResponse" %s
+    nested_type { name: "LabelsEntry" options { map_entry: true }
+      field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+      field { name: "value" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL } } }
+  service { name: "Library"
+    method { name: "ListBooks" input_type: ".library.ListBooksRequest" output_type: ".library.ListBooksResponse" %s
+      options { [google.api.http] { get: "/v1/books" } } } }`
+
+const (
+	pageSizeInt32   = `field { name: "page_size" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL }`
+	pageTokenString = `field { name: "page_token" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL }`
+	nextTokenString = `field { name: "next_page_token" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }`
+	repeatedBooks   = `field { name: "books" number: 2 type: TYPE_MESSAGE type_name: ".library.Book" label: LABEL_REPEATED }`
+)
+
+func TestAnalysePagination(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestFields  string
+		responseFields string
+		streaming      string
+		want           *data.Pagination
+	}{
+		{
+			name:           "paginated",
+			requestFields:  pageSizeInt32 + pageTokenString,
+			responseFields: nextTokenString + repeatedBooks,
+			want:           &data.Pagination{PageTokenField: "page_token", NextPageTokenField: "next_page_token", ItemsField: "books"},
+		},
+		{
+			name:          "first repeated field",
+			requestFields: pageSizeInt32 + pageTokenString,
+			responseFields: nextTokenString + repeatedBooks +
+				`field { name: "authors" number: 3 type: TYPE_STRING label: LABEL_REPEATED }`,
+			want: &data.Pagination{PageTokenField: "page_token", NextPageTokenField: "next_page_token", ItemsField: "books"},
+		},
+		{
+			name:          "map skipped",
+			requestFields: pageSizeInt32 + pageTokenString,
+			responseFields: nextTokenString +
+				`field { name: "labels" number: 3 type: TYPE_MESSAGE type_name: ".library.ListBooksResponse.LabelsEntry" label: LABEL_REPEATED }` +
+				repeatedBooks,
+			want: &data.Pagination{PageTokenField: "page_token", NextPageTokenField: "next_page_token", ItemsField: "books"},
+		},
+		{
+			name:          "only a map",
+			requestFields: pageSizeInt32 + pageTokenString,
+			responseFields: nextTokenString +
+				`field { name: "labels" number: 3 type: TYPE_MESSAGE type_name: ".library.ListBooksResponse.LabelsEntry" label: LABEL_REPEATED }`,
+		},
+		{
+			name:           "no repeated field",
+			requestFields:  pageSizeInt32 + pageTokenString,
+			responseFields: nextTokenString,
+		},
+		{
+			name:           "int64 page size",
+			requestFields:  `field { name: "page_size" number: 1 type: TYPE_INT64 label: LABEL_OPTIONAL }` + pageTokenString,
+			responseFields: nextTokenString + repeatedBooks,
+		},
+		{
+			name:           "missing page size",
+			requestFields:  pageTokenString,
+			responseFields: nextTokenString + repeatedBooks,
+		},
+		{
+			name:           "bytes page token",
+			requestFields:  pageSizeInt32 + `field { name: "page_token" number: 2 type: TYPE_BYTES label: LABEL_OPTIONAL }`,
+			responseFields: nextTokenString + repeatedBooks,
+		},
+		{
+			name:           "repeated page token",
+			requestFields:  pageSizeInt32 + `field { name: "page_token" number: 2 type: TYPE_STRING label: LABEL_REPEATED }`,
+			responseFields: nextTokenString + repeatedBooks,
+		},
+		{
+			name:           "int32 next page token",
+			requestFields:  pageSizeInt32 + pageTokenString,
+			responseFields: `field { name: "next_page_token" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL }` + repeatedBooks,
+		},
+		{
+			name:           "missing next page token",
+			requestFields:  pageSizeInt32 + pageTokenString,
+			responseFields: repeatedBooks,
+		},
+		{
+			name:           "server streaming",
+			requestFields:  pageSizeInt32 + pageTokenString,
+			responseFields: nextTokenString + repeatedBooks,
+			streaming:      "server_streaming: true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := fmt.Sprintf(paginatedFile, tt.requestFields, tt.responseFields, tt.streaming)
+			_, filesData, err := analyse(t, Options{}, newRequest(t, file))
+			require.NoError(t, err)
+			methods := filesData["library.proto"].Services[0].Methods
+			require.Len(t, methods, 1)
+			assert.Equal(t, tt.want, methods[0].Pagination)
+		})
+	}
+}
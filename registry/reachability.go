@@ -0,0 +1,176 @@
+package registry
+
+import (
+	"log/slog"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// methodTypes returns the types a method refers to, the types of a typed long-running operation
+// replace the operation itself.
+func methodTypes(method *data.Method) []string {
+	types := []string{method.Input.Type}
+	if operation := method.Output.Operation; operation != nil {
+		for _, arg := range []*data.MethodArgument{operation.ResponseType, operation.MetadataType} {
+			if arg != nil {
+				types = append(types, arg.Type)
+			}
+		}
+		return types
+	}
+	return append(types, method.Output.Type)
+}
+
+// messageTypes returns the types the fields of a message refer to, including the keys and values of
+// maps.
+func (r *Registry) messageTypes(msg *data.Message) []string {
+	types := make([]string, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		types = append(types, f.Type)
+		if typeInfo, ok := r.Types[f.Type]; ok && typeInfo.IsMapEntry {
+			types = append(types, typeInfo.KeyType.Type, typeInfo.ValueType.Type)
+		}
+	}
+	return types
+}
+
+// reachableTypes returns the set of the types reachable from the given types through the fields of
+// messages.
+func (r *Registry) reachableTypes(roots []string) map[string]bool {
+	reachable := make(map[string]bool)
+	pending := append([]string{}, roots...)
+	for len(pending) > 0 {
+		fqName := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reachable[fqName] {
+			continue
+		}
+		typeInfo, ok := r.Types[fqName]
+		if !ok {
+			continue
+		}
+		reachable[fqName] = true
+		if typeInfo.Message != nil {
+			pending = append(pending, r.messageTypes(typeInfo.Message)...)
+		}
+		if typeInfo.IsMapEntry {
+			pending = append(pending, typeInfo.KeyType.Type, typeInfo.ValueType.Type)
+		}
+	}
+	return reachable
+}
+
+// pruneFilteredTypes removes the messages and enums of the files to generate which are only reachable
+// from the methods left out by include, exclude and visibility_labels. Types which aren't reachable
+// from any method are kept.
+func (r *Registry) pruneFilteredTypes(filesData map[string]*data.File) {
+	if len(r.filteredTypes) == 0 {
+		return
+	}
+	roots := r.serviceTypes(filesData)
+	fromMethods := r.reachableTypes(append(append([]string{}, roots...), r.filteredTypes...))
+	for _, fileData := range r.filesToGenerate(filesData) {
+		for _, msg := range fileData.Messages {
+			if !fromMethods[msg.FQType] {
+				roots = append(roots, msg.FQType)
+			}
+		}
+		for _, enum := range fileData.Enums {
+			if !fromMethods[enum.FQType] {
+				roots = append(roots, enum.FQType)
+			}
+		}
+	}
+	r.pruneTypes(filesData, r.reachableTypes(roots))
+}
+
+// pruneUnreachableTypes removes the messages and enums of the files to generate which aren't reachable
+// from the methods of the files to generate. The types of the files to generate which declare no
+// service are all kept, since these files are generated for the types they declare.
+func (r *Registry) pruneUnreachableTypes(
+	files []*descriptorpb.FileDescriptorProto, filesData map[string]*data.File) {
+	roots := r.serviceTypes(filesData)
+	for _, f := range files {
+		fileData, ok := filesData[f.GetName()]
+		if !ok || !r.IsFileToGenerate(f.GetName()) || len(f.GetService()) > 0 {
+			continue
+		}
+		for _, msg := range fileData.Messages {
+			roots = append(roots, msg.FQType)
+		}
+		for _, enum := range fileData.Enums {
+			roots = append(roots, enum.FQType)
+		}
+	}
+	r.pruneTypes(filesData, r.reachableTypes(roots))
+}
+
+// pruneTypes removes the messages and enums of the files to generate which aren't part of the kept
+// types, along with the imports they required.
+func (r *Registry) pruneTypes(filesData map[string]*data.File, kept map[string]bool) {
+	for _, fileData := range r.filesToGenerate(filesData) {
+		messages := make([]*data.Message, 0, len(fileData.Messages))
+		referenced := make(map[string]bool)
+		for _, msg := range fileData.Messages {
+			if !kept[msg.FQType] {
+				slog.Debug("pruning unreachable message", slog.String("type", msg.FQType))
+				continue
+			}
+			messages = append(messages, msg)
+			for _, t := range r.messageTypes(msg) {
+				referenced[t] = true
+			}
+		}
+		fileData.Messages = messages
+
+		enums := make([]*data.Enum, 0, len(fileData.Enums))
+		for _, enum := range fileData.Enums {
+			if !kept[enum.FQType] {
+				slog.Debug("pruning unreachable enum", slog.String("type", enum.FQType))
+				continue
+			}
+			enums = append(enums, enum)
+		}
+		fileData.Enums = enums
+
+		for _, service := range fileData.Services {
+			for _, method := range service.Methods {
+				for _, t := range methodTypes(method) {
+					referenced[t] = true
+				}
+			}
+		}
+		dependingTypes := make([]string, 0, len(fileData.ExternalDependingTypes))
+		for _, t := range fileData.ExternalDependingTypes {
+			if referenced[t] {
+				dependingTypes = append(dependingTypes, t)
+			}
+		}
+		fileData.ExternalDependingTypes = dependingTypes
+	}
+}
+
+// serviceTypes returns the types the methods of the files to generate refer to.
+func (r *Registry) serviceTypes(filesData map[string]*data.File) []string {
+	types := make([]string, 0)
+	for _, fileData := range r.filesToGenerate(filesData) {
+		for _, service := range fileData.Services {
+			for _, method := range service.Methods {
+				types = append(types, methodTypes(method)...)
+			}
+		}
+	}
+	return types
+}
+
+// filesToGenerate returns the data of the files to generate.
+func (r *Registry) filesToGenerate(filesData map[string]*data.File) []*data.File {
+	files := make([]*data.File, 0, len(filesData))
+	for name, fileData := range filesData {
+		if r.IsFileToGenerate(name) {
+			files = append(files, fileData)
+		}
+	}
+	return files
+}
@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shelfFile = `
+name: "shelf.proto"
+package: "shelf"
+dependency: "book.proto"
+message_type {
+  name: "Shelf"
+  field { name: "books" number: 1 type: TYPE_MESSAGE type_name: ".book.Book" label: LABEL_REPEATED }
+  field { name: "labels" number: 2 type: TYPE_MESSAGE type_name: ".shelf.Shelf.LabelsEntry" label: LABEL_REPEATED }
+  nested_type {
+    name: "LabelsEntry"
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+    field { name: "value" number: 2 type: TYPE_MESSAGE type_name: ".shelf.Label" label: LABEL_OPTIONAL }
+    options { map_entry: true }
+  }
+}
+message_type { name: "Label" field { name: "color" number: 1 type: TYPE_ENUM type_name: ".shelf.Color" label: LABEL_OPTIONAL } }
+message_type { name: "Archive" field { name: "book" number: 1 type: TYPE_MESSAGE type_name: ".book.Book" label: LABEL_OPTIONAL } }
+enum_type { name: "Color" value { name: "COLOR_UNSPECIFIED" number: 0 } }
+enum_type { name: "Size" value { name: "SIZE_UNSPECIFIED" number: 0 } }
+service {
+  name: "Shelves"
+  method { name: "GetShelf" input_type: ".shelf.Shelf" output_type: ".shelf.Shelf"
+    options { [google.api.http] { post: "/v1/shelves" body: "*" } } }
+}
+`
+
+const bookFile = `
+name: "book.proto"
+package: "book"
+message_type { name: "Book" field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+message_type { name: "Author" }
+enum_type { name: "Genre" value { name: "GENRE_UNSPECIFIED" number: 0 } }
+`
+
+// typeNames returns the fully qualified names of the messages and enums of a file.
+func typeNames(fileData *data.File) []string {
+	names := make([]string, 0, len(fileData.Messages)+len(fileData.Enums))
+	for _, msg := range fileData.Messages {
+		names = append(names, msg.FQType)
+	}
+	for _, enum := range fileData.Enums {
+		names = append(names, enum.FQType)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPruneUnreachableTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		generate []string
+		want     map[string][]string
+	}{
+		{
+			name:     "services",
+			files:    []string{bookFile, shelfFile},
+			generate: []string{"shelf.proto"},
+			want:     map[string][]string{"shelf.proto": {".shelf.Color", ".shelf.Label", ".shelf.Shelf"}},
+		},
+		{
+			name:     "file without services",
+			files:    []string{bookFile},
+			generate: []string{"book.proto"},
+			want:     map[string][]string{"book.proto": {".book.Author", ".book.Book", ".book.Genre"}},
+		},
+		{
+			name:     "file without services generated with the services",
+			files:    []string{bookFile, shelfFile},
+			generate: []string{"book.proto", "shelf.proto"},
+			want: map[string][]string{
+				"book.proto":  {".book.Author", ".book.Book", ".book.Genre"},
+				"shelf.proto": {".shelf.Color", ".shelf.Label", ".shelf.Shelf"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(t, tt.files...)
+			req.FileToGenerate = tt.generate
+			_, filesData, err := analyse(t, Options{PruneUnreachable: true}, req)
+			require.NoError(t, err)
+			for fileName, want := range tt.want {
+				assert.Equal(t, want, typeNames(filesData[fileName]), fileName)
+			}
+		})
+	}
+}
+
+func TestPruneTypes(t *testing.T) {
+	req := newRequest(t, bookFile, shelfFile)
+	req.FileToGenerate = []string{"shelf.proto"}
+	r, filesData, err := analyse(t, Options{}, req)
+	require.NoError(t, err)
+	shelf := filesData["shelf.proto"]
+	assert.Contains(t, shelf.ExternalDependingTypes, ".book.Book")
+
+	r.pruneTypes(filesData, map[string]bool{".shelf.Shelf": true, ".shelf.Size": true, ".book.Book": true})
+	assert.Equal(t, []string{".shelf.Shelf", ".shelf.Size"}, typeNames(shelf))
+	// Archive is pruned but Shelf still imports Book
+	assert.Contains(t, shelf.ExternalDependingTypes, ".book.Book")
+	// the files which aren't generated are left as they are
+	assert.Equal(t, []string{".book.Author", ".book.Book", ".book.Genre"}, typeNames(filesData["book.proto"]))
+
+	r.pruneTypes(filesData, map[string]bool{".shelf.Size": true})
+	assert.Equal(t, []string{".shelf.Size"}, typeNames(shelf))
+	assert.Empty(t, shelf.ExternalDependingTypes)
+}
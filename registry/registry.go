@@ -11,12 +11,53 @@ import (
 	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // importRootSeparator separates the ts import root inside ts_import_roots & ts_import_root_aliases.
 const importRootSeparator = ";"
 
+// Values accepted by the runtime_validators parameter.
+const (
+	// RuntimeValidatorsNone disables the generation of runtime validators.
+	RuntimeValidatorsNone = "none"
+	// RuntimeValidatorsZod generates a zod schema for each message and enum.
+	RuntimeValidatorsZod = "zod"
+	// RuntimeValidatorsStandalone generates dependency free checks for each message and enum.
+	RuntimeValidatorsStandalone = "standalone"
+)
+
+// Values accepted by the enum_style parameter.
+const (
+	// EnumStyleEnum renders enums as typescript enums.
+	EnumStyleEnum = "enum"
+	// EnumStyleUnion renders enums as unions of literal types.
+	EnumStyleUnion = "union"
+	// EnumStyleConstObject renders enums as const objects along with the union of their values.
+	EnumStyleConstObject = "const_object"
+)
+
+// Values accepted by the unbound_methods parameter.
+const (
+	// UnboundMethodsGenerate generates a POST /package.Service/Method binding for methods without HTTP
+	// rules, which the gateway serves with generate_unbound_methods=true.
+	UnboundMethodsGenerate = "generate"
+	// UnboundMethodsSkip leaves methods without HTTP rules out of the generated clients.
+	UnboundMethodsSkip = "skip"
+	// UnboundMethodsError fails the generation when a method has no HTTP rule.
+	UnboundMethodsError = "error"
+)
+
+// Values accepted by the nested_style parameter.
+const (
+	// NestedStyleFlat declares nested messages and enums at the top level, their names prefixed with
+	// the names of their parents.
+	NestedStyleFlat = "flat"
+	// NestedStyleNamespace declares nested messages and enums in namespaces named after their parents.
+	NestedStyleNamespace = "namespace"
+)
+
 type Options struct {
 	// TSImportRootParamsKey contains the key for common_import_root in parameters
 	TSImportRoots string
@@ -26,6 +67,9 @@ type Options struct {
 	FetchModuleDirectory string
 	// FetchModuleFilename is the file name for the individual fetch module
 	FetchModuleFilename string
+	// GRPCAPIConfiguration is the path of a gRPC API configuration YAML file declaring the HTTP rules of
+	// methods, in addition to their google.api.http annotations
+	GRPCAPIConfiguration string
 	// UseProtoNames will generate field names the same as defined in the proto
 	UseProtoNames bool
 	// UseStaticClasses will cause the generator to generate a static class in the form ServiceName.MethodName, which is
@@ -37,6 +81,81 @@ type Options struct {
 	EmitUnpopulated bool
 	// EnableStylingCheck enables both eslint and tsc check for the generated code
 	EnableStylingCheck bool
+	// GenerateFakeServers generates an interface for each service along with a router that dispatches
+	// requests to an in-memory implementation of it, which can be plugged into the fetch module.
+	GenerateFakeServers bool
+	// RuntimeValidators selects the kind of runtime validators generated for messages and enums, one
+	// of none, zod or standalone.
+	RuntimeValidators string
+	// StringTimeTypes types Timestamp and Duration fields as strings, their JSON encoding, rather than
+	// as the messages of timestamp.proto and duration.proto.
+	StringTimeTypes bool
+	// GenerateConstraintValidators generates a validate function for each message that checks the
+	// buf.validate and protoc-gen-validate rules declared on its fields.
+	GenerateConstraintValidators bool
+	// GenerateFactories generates a function creating each message with its default values, along
+	// with a function filling in the default values of a message.
+	GenerateFactories bool
+	// GenerateTaggedOneOfs generates a variant of each message with oneofs in which each oneof is a
+	// tagged union, along with the functions converting it to and from the message.
+	GenerateTaggedOneOfs bool
+	// EnumStyle selects how enums are rendered, one of enum, union or const_object.
+	EnumStyle string
+	// NestedStyle selects how nested messages and enums are declared, one of flat or namespace.
+	NestedStyle string
+	// UseEnumNumbers mirrors the grpc gateway protojson configuration of the same name, enum values
+	// are typed as numbers rather than names.
+	UseEnumNumbers bool
+	// GenerateEnumHelpers generates a list of the values of each enum along with functions converting
+	// them to and from numbers.
+	GenerateEnumHelpers bool
+	// OpenEnums adds unknown values to the type of enums rendered as unions or const objects, so that
+	// values added to the proto after the client was generated are accounted for.
+	OpenEnums bool
+	// GenerateEnumLabels generates the labels of the values of each enum along with a list of the
+	// values to pick from in select widgets.
+	GenerateEnumLabels bool
+	// GenerateDescriptors generates a descriptor of each message and enum which can be inspected at
+	// runtime, the descriptors are registered in the fetch module.
+	GenerateDescriptors bool
+	// GenerateMessageUtils generates functions comparing, copying and merging each message with the
+	// semantics of protobuf.
+	GenerateMessageUtils bool
+	// GenerateUpdateMasks generates a function listing the paths of the fields set in each message,
+	// along with a function computing the update mask of PATCH methods from their body.
+	GenerateUpdateMasks bool
+	// GeneratePageIterators generates async iterators over the pages and items of the List methods
+	// following AIP-158.
+	GeneratePageIterators bool
+	// GenerateOperationHelpers types the google.longrunning.Operation returned by methods annotated
+	// with google.longrunning.operation_info, and generates a function waiting for each of them.
+	GenerateOperationHelpers bool
+	// GenerateResourceNames generates functions formatting and parsing the names of the resources
+	// declared with google.api.resource, fields referencing them are typed with branded strings.
+	GenerateResourceNames bool
+	// UnboundMethods selects how methods without HTTP rules are handled, one of generate, skip or
+	// error.
+	UnboundMethods string
+	// Include lists glob patterns separated by ; matching the fully qualified names of the services and
+	// methods to generate, every method is generated when it is empty.
+	Include string
+	// Exclude lists glob patterns separated by ; matching the fully qualified names of the services and
+	// methods left out, exclusions win over inclusions.
+	Exclude string
+	// VisibilityLabels lists the google.api.visibility labels separated by ; of the services and
+	// methods to generate, restrictions are ignored when it is empty.
+	VisibilityLabels string
+	// PruneUnreachable only generates the messages and enums reachable from the inputs and outputs of
+	// the methods of the files to generate, the files to generate without services keep their types.
+	PruneUnreachable bool
+	// WarningsAsErrors fails the generation on warnings, as it does on errors.
+	WarningsAsErrors bool
+	// DiagnosticsReport is the name of a file generated along with the typescript files which lists
+	// the warnings, they are printed to stderr when it is empty.
+	DiagnosticsReport string
+	// EnumPrefixStrip removes the name of the enum from the keys of its values, e.g. Color.RED
+	// rather than Color.COLOR_RED, the values themselves keep the names used by the gateway.
+	EnumPrefixStrip bool
 }
 
 // Registry analyze generation request, spits out the data the the rendering process
@@ -61,10 +180,113 @@ type Registry struct {
 
 	// TSPackages stores the package name keyed by the TS file name
 	TSPackages map[string]string
+
+	// Resources stores the resources declared in every file keyed by their resource type
+	Resources map[string]*ResourceInformation
+
+	// httpRules stores the HTTP rules read from the gRPC API configuration keyed by the fully qualified
+	// name of their method
+	httpRules map[string][]*httpBinding
+
+	// includes and excludes are the patterns of Include and Exclude
+	includes []string
+	excludes []string
+
+	// visibilityLabels is the set of the labels of VisibilityLabels, it is nil when restrictions are
+	// ignored
+	visibilityLabels map[string]bool
+
+	// filteredTypes stores the inputs and outputs of the methods of the files to generate left out by
+	// the filters
+	filteredTypes []string
+
+	// spans stores the spans of the source code info of each analysed file keyed by their path, so that
+	// the problems found once every file is analysed can be located as well
+	spans map[string]map[string][]int32
+
+	// extensionTypes stores the extensions declared by the files of the request, used to read the
+	// options of extensions the plugin has no Go types for
+	extensionTypes *dynamicpb.Types
+
+	// routes stores the fully qualified name of the method bound to every HTTP method and path of the
+	// files to generate, keyed by the HTTP method followed by the path with wildcards for variables
+	routes map[string]string
+
+	// symbols stores the declarations of every file keyed by the file name, declared stores them once
+	// resolved keyed by the file name and then by the identifiers they declare
+	symbols  map[string][]*symbol
+	declared map[string]map[string]*symbol
+
+	// typeImports stores the types imported by every file for the ts_type option of their fields keyed
+	// by the file name
+	typeImports map[string][]*typeImport
+
+	// outputPaths and moduleNames store the output_path and module_name options of the files keyed by
+	// the file name
+	outputPaths map[string]string
+	moduleNames map[string]string
+
+	// streamingIterators tells whether a method of the files to generate returns its responses as an
+	// AsyncIterable
+	streamingIterators bool
+
+	// moduleIdentifiers stores the identifier of every file in the files importing it keyed by the file
+	// name
+	moduleIdentifiers map[string]string
+
+	// diagnostics and warnings store the errors and the warnings found in the files to generate
+	diagnostics Diagnostics
+	warnings    Diagnostics
+
+	// comments stores the leading comments of enum values keyed by the fully qualified name of their
+	// enum followed by their name
+	comments map[string]string
 }
 
 // NewRegistry initialise the registry and return the instance.
 func NewRegistry(opts Options) (*Registry, error) {
+	switch opts.RuntimeValidators {
+	case "":
+		opts.RuntimeValidators = RuntimeValidatorsNone
+	case RuntimeValidatorsNone, RuntimeValidatorsZod, RuntimeValidatorsStandalone:
+	default:
+		return nil, errors.Errorf("invalid runtime_validators %q, must be one of none, zod or standalone",
+			opts.RuntimeValidators)
+	}
+
+	switch opts.EnumStyle {
+	case "":
+		opts.EnumStyle = EnumStyleEnum
+	case EnumStyleEnum, EnumStyleUnion, EnumStyleConstObject:
+	default:
+		return nil, errors.Errorf("invalid enum_style %q, must be one of enum, union or const_object", opts.EnumStyle)
+	}
+
+	switch opts.UnboundMethods {
+	case "":
+		opts.UnboundMethods = UnboundMethodsGenerate
+	case UnboundMethodsGenerate, UnboundMethodsSkip, UnboundMethodsError:
+	default:
+		return nil, errors.Errorf("invalid unbound_methods %q, must be one of generate, skip or error", opts.UnboundMethods)
+	}
+
+	switch opts.NestedStyle {
+	case "":
+		opts.NestedStyle = NestedStyleFlat
+	case NestedStyleFlat, NestedStyleNamespace:
+	default:
+		return nil, errors.Errorf("invalid nested_style %q, must be one of flat or namespace", opts.NestedStyle)
+	}
+
+	includes, err := parseFilterPatterns("include", opts.Include)
+	if err != nil {
+		return nil, err
+	}
+	excludes, err := parseFilterPatterns("exclude", opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
 	tsImportRoots, tsImportRootAliases, err := getTSImportRootInformation(opts)
 	slog.Debug("found ts import roots", slog.Any("importRoots", tsImportRoots))
 	slog.Debug("found ts import root aliases", slog.Any("importRootAliases", tsImportRootAliases))
@@ -72,6 +294,14 @@ func NewRegistry(opts Options) (*Registry, error) {
 		return nil, errors.Wrap(err, "error getting common import root information")
 	}
 
+	httpRules := make(map[string][]*httpBinding)
+	if opts.GRPCAPIConfiguration != "" {
+		httpRules, err = loadHTTPRules(opts.GRPCAPIConfiguration)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading gRPC API configuration")
+		}
+	}
+
 	slog.Debug("found fetch module directory", slog.String("moduleDir", opts.FetchModuleDirectory))
 	slog.Debug("found fetch module name", slog.String("moduleName", opts.FetchModuleFilename))
 
@@ -79,6 +309,11 @@ func NewRegistry(opts Options) (*Registry, error) {
 		Options:             opts,
 		Types:               make(map[string]*TypeInformation),
 		TSPackages:          make(map[string]string),
+		Resources:           make(map[string]*ResourceInformation),
+		httpRules:           httpRules,
+		includes:            includes,
+		excludes:            excludes,
+		visibilityLabels:    parseVisibilityLabels(opts.VisibilityLabels),
 		TSImportRoots:       tsImportRoots,
 		TSImportRootAliases: tsImportRootAliases,
 	}, nil
@@ -136,16 +371,52 @@ type TypeInformation struct {
 	// ModuleIdentifier is the identifier of the type inside the package, this will be useful for enum
 	// and nested enum.
 	PackageIdentifier string
+	// Namespace is the namespace the type is declared in when nested types are rendered in namespaces,
+	// e.g. Outer for Outer.Inner, PackageIdentifier is then prefixed with it.
+	Namespace string
 	// LocalIdentifier is the identifier inside the types local scope
 	LocalIdentifier string
 	// ProtoType is the type inside the proto. This is used to tell whether it's an enum or a message
 	ProtoType descriptorpb.FieldDescriptorProto_Type
 	// IsMapEntry indicates whether this type is a Map Entry
 	IsMapEntry bool
+	// IsSkipped indicates the message is left out of the generated files with ts_message.skip
+	IsSkipped bool
 	// KeyType is the type information for the map key
 	KeyType *data.MapEntryType
 	// Value type is the type information for the map value
 	ValueType *data.MapEntryType
+	// Message is the rendering data of the message, it allows the fields of types declared in other
+	// files to be looked up. It is nil for enums, services and map entries.
+	Message *data.Message
+	// EnumValues maps the numbers of an enum to the names of its values, the first name wins for
+	// aliases. It is nil for other types.
+	EnumValues map[int32]string
+	// EnumDefault is the name of the first value of an enum, which is its default value.
+	EnumDefault string
+	// EnumDefaultKey is the key of the default value of an enum in typescript.
+	EnumDefaultKey string
+}
+
+// RequiresFetchModule returns whether the file needs to import the fetch module, either to call its
+// services or for the runtime checks used by standalone and constraint validators.
+func (r *Registry) RequiresFetchModule(fileData *data.File) bool {
+	if fileData.Services.RequiresFetchModule() {
+		return true
+	}
+	if r.GenerateConstraintValidators && len(fileData.Messages) > 0 {
+		return true
+	}
+	if r.GenerateDescriptors && (len(fileData.Messages) > 0 || len(fileData.Enums) > 0) {
+		return true
+	}
+	if (r.GenerateMessageUtils || r.GenerateUpdateMasks) && len(fileData.Messages) > 0 {
+		return true
+	}
+	if r.GenerateResourceNames && len(fileData.Resources) > 0 {
+		return true
+	}
+	return r.RuntimeValidators == RuntimeValidatorsStandalone && (len(fileData.Messages) > 0 || len(fileData.Enums) > 0)
 }
 
 // IsFileToGenerate contains the file to be generated in the request.
@@ -160,6 +431,19 @@ func (r *Registry) Analyse(req *pluginpb.CodeGeneratorRequest) (map[string]*data
 	for _, f := range req.GetFileToGenerate() {
 		r.FilesToGenerate[f] = true
 	}
+	r.filteredTypes = make([]string, 0)
+	r.routes = make(map[string]string)
+	r.diagnostics = nil
+	r.warnings = nil
+	r.symbols = make(map[string][]*symbol)
+	r.spans = make(map[string]map[string][]int32)
+	r.typeImports = make(map[string][]*typeImport)
+	r.outputPaths = make(map[string]string)
+	r.moduleNames = make(map[string]string)
+	r.streamingIterators = false
+	if err := r.analyseExtensionTypes(req); err != nil {
+		return nil, err
+	}
 
 	files := req.GetProtoFile()
 	slog.Debug("about to start anaylyse files", slog.Int("count", len(files)))
@@ -173,6 +457,21 @@ func (r *Registry) Analyse(req *pluginpb.CodeGeneratorRequest) (map[string]*data
 		data[f.GetName()] = fileData
 	}
 
+	r.checkHTTPRuleSelectors(files)
+	// skipped messages are checked with the names of the proto files, before they are resolved
+	r.checkSkippedMessages(files, data)
+	r.resolveSymbols(files)
+
+	if len(r.diagnostics) > 0 {
+		return nil, r.diagnostics
+	}
+
+	if r.PruneUnreachable {
+		r.pruneUnreachableTypes(files, data)
+	} else {
+		r.pruneFilteredTypes(data)
+	}
+
 	// when finishes we have a full map of types and where they are located
 	// collect all the external dependencies and back fill it to the file data.
 	err := r.collectExternalDependenciesFromData(data)
@@ -183,8 +482,23 @@ func (r *Registry) Analyse(req *pluginpb.CodeGeneratorRequest) (map[string]*data
 	return data, nil
 }
 
+// UsesStreamingIterators returns whether a method of the files to generate returns its responses as
+// an AsyncIterable, which requires the fetch module to support it.
+func (r *Registry) UsesStreamingIterators() bool {
+	return r.streamingIterators
+}
+
+// Warnings returns the warnings found by the last analysis.
+func (r *Registry) Warnings() Diagnostics {
+	return r.warnings
+}
+
 // This simply just concats the parents name and the entity name.
 func (r *Registry) getNameOfPackageLevelIdentifier(parents []string, name string) string {
+	if r.NestedStyle == NestedStyleNamespace {
+		// the namespace of nested types is set once the identifiers of their parents are resolved
+		return name
+	}
 	return strings.Join(parents, "") + name
 }
 
@@ -300,55 +614,25 @@ func (r *Registry) collectExternalDependenciesFromData(filesData map[string]*dat
 		for _, typeName := range fileData.ExternalDependingTypes {
 			typeInfo, ok := r.Types[typeName]
 			if !ok {
-				return errors.Errorf("cannot find type info for %s, $v", typeName)
+				return errors.Errorf("cannot find type info for %s, required by %s", typeName, fileData.Name)
 			}
-			if typeInfo.File == "google/protobuf/wrappers.proto" {
-				// Skip well-known wrapper types without importing them as an external dependency,
-				// since their types are converted to native TypeScript types by mapWellKnownType.
+			if typeInfo.File == "google/protobuf/wrappers.proto" || typeInfo.File == "google/protobuf/field_mask.proto" {
+				// Skip well-known wrapper types and field masks without importing them as an external
+				// dependency, since their types are converted to native TypeScript types by mapWellKnownType.
 				continue
 			}
-			identifier := typeInfo.Package + "|" + typeInfo.File
-
-			if _, ok := dependencies[identifier]; !ok {
-				// only fill in if this file has not been mentioned before.
-				// the way import in the generated file works is like
-				// import * as [ModuleIdentifier] from '[Source File]'
-				// so there only needs to be added once.
-				// Referencing types will be [ModuleIdentifier].[PackageIdentifier]
-				base := fileData.TSFileName
-				target := data.GetTSFileName(typeInfo.File)
-				var sourceFile string
-				if pkg, ok := r.TSPackages[target]; ok {
-					slog.Debug("package import override has been found", slog.String("pkg", pkg), slog.String("target", target))
-					sourceFile = pkg
-				} else {
-					foundAtRoot, alias, err := r.findRootAliasForPath(func(absRoot string) (bool, error) {
-						completePath := filepath.Join(absRoot, typeInfo.File)
-						_, err := os.Stat(completePath)
-						if err != nil {
-							if os.IsNotExist(err) {
-								return false, nil
-							}
-							return false, err
-						}
-						return true, nil
-					})
-					if err != nil {
-						return errors.WithStack(err)
-					}
-
-					if foundAtRoot != "" {
-						target = filepath.Join(foundAtRoot, target)
-					}
-
-					sourceFile, err = r.getSourceFileForImport(base, target, foundAtRoot, alias)
-					if err != nil {
-						return errors.Wrap(err, "error getting source file for import")
-					}
-				}
-				dependencies[identifier] = &data.Dependency{
-					ModuleIdentifier: data.GetModuleName(typeInfo.Package, typeInfo.File),
-					SourceFile:       sourceFile,
+			if r.StringTimeTypes &&
+				(typeInfo.File == "google/protobuf/timestamp.proto" || typeInfo.File == "google/protobuf/duration.proto") {
+				continue
+			}
+			if err := r.addFileDependency(dependencies, fileData, typeInfo.Package, typeInfo.File); err != nil {
+				return err
+			}
+		}
+		if r.GenerateResourceNames {
+			for _, info := range r.resolveResourceReferences(fileData) {
+				if err := r.addFileDependency(dependencies, fileData, info.Package, info.File); err != nil {
+					return err
 				}
 			}
 		}
@@ -360,3 +644,54 @@ func (r *Registry) collectExternalDependenciesFromData(filesData map[string]*dat
 
 	return nil
 }
+
+// addFileDependency adds the import of a file to the dependencies of another file, unless it has
+// already been added.
+func (r *Registry) addFileDependency(
+	dependencies map[string]*data.Dependency, fileData *data.File, packageName, fileName string) error {
+	identifier := packageName + "|" + fileName
+
+	if _, ok := dependencies[identifier]; !ok {
+		// only fill in if this file has not been mentioned before.
+		// the way import in the generated file works is like
+		// import * as [ModuleIdentifier] from '[Source File]'
+		// so there only needs to be added once.
+		// Referencing types will be [ModuleIdentifier].[PackageIdentifier]
+		base := fileData.TSFileName
+		target := r.getTSFileName(fileName)
+		var sourceFile string
+		if pkg, ok := r.TSPackages[target]; ok {
+			slog.Debug("package import override has been found", slog.String("pkg", pkg), slog.String("target", target))
+			sourceFile = pkg
+		} else {
+			foundAtRoot, alias, err := r.findRootAliasForPath(func(absRoot string) (bool, error) {
+				completePath := filepath.Join(absRoot, fileName)
+				_, err := os.Stat(completePath)
+				if err != nil {
+					if os.IsNotExist(err) {
+						return false, nil
+					}
+					return false, err
+				}
+				return true, nil
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if foundAtRoot != "" {
+				target = filepath.Join(foundAtRoot, target)
+			}
+
+			sourceFile, err = r.getSourceFileForImport(base, target, foundAtRoot, alias)
+			if err != nil {
+				return errors.Wrap(err, "error getting source file for import")
+			}
+		}
+		dependencies[identifier] = &data.Dependency{
+			ModuleIdentifier: r.ModuleIdentifier(packageName, fileName),
+			SourceFile:       sourceFile,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newRequest builds a request generating every file, the files are written in the text format of
+// FileDescriptorProto.
+func newRequest(t *testing.T, files ...string) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{}
+	for _, text := range files {
+		f := &descriptorpb.FileDescriptorProto{}
+		require.NoError(t, prototext.Unmarshal([]byte(text), f))
+		req.ProtoFile = append(req.ProtoFile, f)
+		req.FileToGenerate = append(req.FileToGenerate, f.GetName())
+	}
+	return req
+}
+
+// analyse runs the analysis of a request with a new registry.
+func analyse(
+	t *testing.T, opts Options, req *pluginpb.CodeGeneratorRequest) (*Registry, map[string]*data.File, error) {
+	t.Helper()
+	r, err := NewRegistry(opts)
+	require.NoError(t, err)
+	filesData, err := r.Analyse(req)
+	return r, filesData, err
+}
+
+// diagnosticMessages returns the messages of the diagnostics returned by Analyse, or of its warnings.
+func diagnosticMessages(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var diagnostics Diagnostics
+	require.True(t, errors.As(err, &diagnostics), "unexpected error: %v", err)
+	messages := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.Message)
+	}
+	return messages
+}
+
+// warningMessages returns the messages of the warnings found by the last analysis.
+func warningMessages(r *Registry) []string {
+	if len(r.Warnings()) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(r.Warnings()))
+	for _, warning := range r.Warnings() {
+		messages = append(messages, warning.Message)
+	}
+	return messages
+}
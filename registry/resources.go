@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// resourceNameField is the field holding the name of a resource when name_field isn't set.
+const resourceNameField = "name"
+
+// ResourceInformation stores the information about a resource declared with google.api.resource or
+// google.api.resource_definition.
+type ResourceInformation struct {
+	// Package is the package of the file declaring the resource
+	Package string
+	// File is the file declaring the resource
+	File string
+	// Resource is the rendering data of the resource
+	Resource *data.Resource
+}
+
+// analyseFileResources registers the resources declared with google.api.resource_definition in the
+// options of a file.
+func (r *Registry) analyseFileResources(fileData *data.File, packageName, fileName string, opts *descriptorpb.FileOptions) {
+	if opts == nil || !proto.HasExtension(opts, annotations.E_ResourceDefinition) {
+		return
+	}
+	definitions, _ := proto.GetExtension(opts, annotations.E_ResourceDefinition).([]*annotations.ResourceDescriptor)
+	for _, descriptor := range definitions {
+		r.registerResource(fileData, packageName, fileName, descriptor)
+	}
+}
+
+// analyseMessageResource registers the resource declared with google.api.resource on a message, its
+// name field references the resource.
+func (r *Registry) analyseMessageResource(
+	fileData *data.File, packageName, fileName string, msgData *data.Message, opts *descriptorpb.MessageOptions) {
+	if opts == nil || !proto.HasExtension(opts, annotations.E_Resource) {
+		return
+	}
+	descriptor, ok := proto.GetExtension(opts, annotations.E_Resource).(*annotations.ResourceDescriptor)
+	if !ok || !r.registerResource(fileData, packageName, fileName, descriptor) {
+		return
+	}
+	nameField := descriptor.GetNameField()
+	if nameField == "" {
+		nameField = resourceNameField
+	}
+	for _, f := range msgData.Fields {
+		if f.Name == nameField && f.Type == "string" && f.Resource == nil {
+			f.Resource = &data.ResourceReference{Type: descriptor.GetType()}
+			fileData.ResourceReferences = append(fileData.ResourceReferences, f.Resource)
+		}
+	}
+}
+
+// registerResource registers a resource in the registry and in the file declaring it, it returns false
+// when the resource has no patterns or has already been declared.
+func (r *Registry) registerResource(
+	fileData *data.File, packageName, fileName string, descriptor *annotations.ResourceDescriptor) bool {
+	resourceType := descriptor.GetType()
+	if resourceType == "" || len(descriptor.GetPattern()) == 0 {
+		return false
+	}
+	if _, ok := r.Resources[resourceType]; ok {
+		return false
+	}
+	resource := &data.Resource{
+		Type:     resourceType,
+		Name:     resourceType[strings.LastIndex(resourceType, "/")+1:] + "Name",
+		Patterns: descriptor.GetPattern(),
+	}
+	r.Resources[resourceType] = &ResourceInformation{
+		Package:  packageName,
+		File:     fileName,
+		Resource: resource,
+	}
+	fileData.Resources = append(fileData.Resources, resource)
+	r.declareResource(fileName, resource)
+	return true
+}
+
+// analyseFieldResourceReference records the resource referenced with google.api.resource_reference by
+// a string field, references to any resource and to child resources are left as strings.
+func analyseFieldResourceReference(fileData *data.File, fieldData *data.Field, opts *descriptorpb.FieldOptions) {
+	if opts == nil || fieldData.Type != "string" || !proto.HasExtension(opts, annotations.E_ResourceReference) {
+		return
+	}
+	reference, ok := proto.GetExtension(opts, annotations.E_ResourceReference).(*annotations.ResourceReference)
+	if !ok || reference.GetType() == "" || reference.GetType() == "*" {
+		return
+	}
+	fieldData.Resource = &data.ResourceReference{Type: reference.GetType()}
+	fileData.ResourceReferences = append(fileData.ResourceReferences, fieldData.Resource)
+}
+
+// resolveResourceReferences flags the references to resources declared in other files, which have to
+// be imported. References to unknown resources are left as is and rendered as strings.
+func (r *Registry) resolveResourceReferences(fileData *data.File) []*ResourceInformation {
+	resources := make([]*ResourceInformation, 0)
+	for _, reference := range fileData.ResourceReferences {
+		info, ok := r.Resources[reference.Type]
+		if !ok || info.File == fileData.Name {
+			continue
+		}
+		reference.IsExternal = true
+		resources = append(resources, info)
+	}
+	return resources
+}
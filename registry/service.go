@@ -2,6 +2,7 @@ package registry
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"golang.org/x/text/cases"
@@ -21,34 +22,9 @@ func getHTTPAnnotation(m *descriptorpb.MethodDescriptorProto) *annotations.HttpR
 	return nil
 }
 
-func hasHTTPAnnotation(m *descriptorpb.MethodDescriptorProto) bool {
-	return getHTTPAnnotation(m) != nil
-}
-
 func extractHTTPMethodPath(rule *annotations.HttpRule) (string, string) {
-	pattern := rule.Pattern
-	switch pattern.(type) {
-	case *annotations.HttpRule_Get:
-		return "GET", rule.GetGet()
-	case *annotations.HttpRule_Post:
-		return "POST", rule.GetPost()
-	case *annotations.HttpRule_Put:
-		return "PUT", rule.GetPut()
-	case *annotations.HttpRule_Patch:
-		return "PATCH", rule.GetPatch()
-	case *annotations.HttpRule_Delete:
-		return "DELETE", rule.GetDelete()
-	default:
-		panic(fmt.Sprintf("unsupported HTTP method %T", pattern))
-	}
-}
-
-func getHTTPBody(m *descriptorpb.MethodDescriptorProto) *string {
-	if !hasHTTPAnnotation(m) {
-		return nil
-	}
-	rule := getHTTPAnnotation(m)
-	return extractHTTPBody(rule)
+	httpMethod, url, _ := httpRulePattern(rule)
+	return httpMethod, url
 }
 
 func extractHTTPBody(rule *annotations.HttpRule) *string {
@@ -64,7 +40,7 @@ func extractHTTPBody(rule *annotations.HttpRule) *string {
 }
 
 // generateTSMethodName generates a unique TypeScript method name for a binding
-func generateTSMethodName(rpcName, httpMethod string, bindingIndex int, existingMethods []*data.Method) string {
+func generateTSMethodName(rpcName, httpMethod string, bindingIndex int, taken map[string]bool) string {
 	// Primary binding uses the RPC method name as-is
 	if bindingIndex == 0 {
 		return rpcName
@@ -76,20 +52,42 @@ func generateTSMethodName(rpcName, httpMethod string, bindingIndex int, existing
 	httpMethodTitle := titleCaser.String(strings.ToLower(httpMethod))
 	baseName := rpcName + httpMethodTitle
 
-	// Check for conflicts with existing method names
-	conflictCount := 0
-	for _, method := range existingMethods {
-		if method.TSMethodName == baseName || strings.HasPrefix(method.TSMethodName, baseName) {
-			conflictCount++
-		}
+	// If there's a conflict, append the first number giving a free name
+	name := baseName
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s%d", baseName, n)
 	}
+	return name
+}
 
-	// If there's a conflict, append a number
-	if conflictCount > 0 {
-		return fmt.Sprintf("%s%d", baseName, conflictCount+1)
+// nameAdditionalBindings names the methods of the additional bindings of a service once the names of
+// the primary bindings, which are the names of the RPCs, and the names set with ts_method, which must
+// be unique in the service, are all known.
+func (r *Registry) nameAdditionalBindings(
+	fileName, fqServiceName string, methods []*data.Method, named map[*data.Method]sourceLocation) {
+	taken := make(map[string]bool)
+	for _, method := range methods {
+		if _, ok := named[method]; !ok && method.BindingIndex == 0 {
+			taken[method.TSMethodName] = true
+		}
+	}
+	for _, method := range methods {
+		loc, ok := named[method]
+		if !ok {
+			continue
+		}
+		if taken[method.TSMethodName] && r.IsFileToGenerate(fileName) {
+			r.report(SeverityError, loc, "%s.%s: the name %s is already used by another method of the service",
+				strings.TrimPrefix(fqServiceName, "."), method.Name, method.TSMethodName)
+		}
+		taken[method.TSMethodName] = true
+	}
+	for _, method := range methods {
+		if _, ok := named[method]; !ok && method.BindingIndex > 0 {
+			method.TSMethodName = generateTSMethodName(method.Name, method.HTTPMethod, method.BindingIndex, taken)
+			taken[method.TSMethodName] = true
+		}
 	}
-
-	return baseName
 }
 
 // createMethodFromRule creates a Method data structure from an HttpRule
@@ -97,7 +95,6 @@ func createMethodFromRule(
 	method *descriptorpb.MethodDescriptorProto,
 	rule *annotations.HttpRule,
 	bindingIndex int,
-	serviceData *data.Service,
 	inputTypeFQName, outputTypeFQName string,
 	isInputTypeExternal, isOutputTypeExternal bool,
 ) *data.Method {
@@ -109,9 +106,6 @@ func createMethodFromRule(
 	}
 	body := extractHTTPBody(rule)
 
-	// Generate TypeScript method name
-	tsMethodName := generateTSMethodName(method.GetName(), httpMethod, bindingIndex, serviceData.Methods)
-
 	return &data.Method{
 		Name: method.GetName(),
 		URL:  url,
@@ -128,13 +122,31 @@ func createMethodFromRule(
 		HTTPMethod:      httpMethod,
 		HTTPRequestBody: body,
 		BindingIndex:    bindingIndex,
-		TSMethodName:    tsMethodName,
+		// additional bindings are named once every method of the service is known
+		TSMethodName: method.GetName(),
+	}
+}
+
+// reportUnboundMethod reports a method without HTTP rules, as an error when they aren't allowed and
+// as a warning otherwise, since the method is either left out or only served by gateways running with
+// generate_unbound_methods=true.
+func (r *Registry) reportUnboundMethod(loc sourceLocation, methodName, route string) {
+	switch r.UnboundMethods {
+	case UnboundMethodsError:
+		r.report(SeverityError, loc, "%s has no HTTP rule, annotate it with google.api.http or set unbound_methods",
+			methodName)
+	case UnboundMethodsSkip:
+		r.report(SeverityWarning, loc, "%s has no HTTP rule and is left out", methodName)
+	default:
+		r.report(SeverityWarning, loc, "%s has no HTTP rule and is called with %s, which the gateway only serves "+
+			"with generate_unbound_methods=true", methodName, route)
 	}
 }
 
 func (r *Registry) analyseService(
 	fileData *data.File,
 	packageName, fileName string,
+	loc sourceLocation,
 	service *descriptorpb.ServiceDescriptorProto) {
 	packageIdentifier := service.GetName()
 	fqName := "." + packageName + "." + packageIdentifier
@@ -151,13 +163,29 @@ func (r *Registry) analyseService(
 	serviceData := data.NewService()
 	serviceData.Name = service.GetName()
 	serviceURLPart := packageName + "." + serviceData.Name
-
-	for _, method := range service.Method {
+	// isLeftOut tells whether methods of the service are left out by the filters or for having no HTTP
+	// rule
+	isLeftOut := false
+	pathPrefix := r.analyseServiceOptions(fileName, serviceData, service, loc)
+	// named stores the location of the names of the bindings named with ts_method
+	named := make(map[*data.Method]sourceLocation)
+
+	for methodIdx, method := range service.Method {
 		// don't support client streaming, will ignore the client streaming method
 		if method.GetClientStreaming() {
 			continue
 		}
 
+		fqMethodName := r.getFullQualifiedName(packageName, []string{serviceData.Name}, method.GetName())
+		if !r.isMethodIncluded(fqName, fqMethodName, service, method) {
+			slog.Debug("method left out by the filters", slog.String("method", fqMethodName))
+			if r.IsFileToGenerate(fileName) {
+				r.filteredTypes = append(r.filteredTypes, method.GetInputType(), method.GetOutputType())
+			}
+			isLeftOut = true
+			continue
+		}
+
 		inputTypeFQName := *method.InputType
 		isInputTypeExternal := r.isExternalDependenciesOutsidePackage(inputTypeFQName, packageName)
 
@@ -168,72 +196,55 @@ func (r *Registry) analyseService(
 		outputTypeFQName := *method.OutputType
 		isOutputTypeExternal := r.isExternalDependenciesOutsidePackage(outputTypeFQName, packageName)
 
-		if isOutputTypeExternal {
+		var operation *data.Operation
+		if r.GenerateOperationHelpers {
+			operation = r.analyseOperation(fileData, packageName, method)
+		}
+
+		// typed operations are rendered with fm.Operation, the operation type itself isn't imported
+		if isOutputTypeExternal && operation == nil {
 			fileData.ExternalDependingTypes = append(fileData.ExternalDependingTypes, outputTypeFQName)
 		}
 
-		// Process primary HTTP binding
-		if hasHTTPAnnotation(method) {
-			rule := getHTTPAnnotation(method)
+		pagination := r.analysePagination(method)
+
+		//nolint:gosec // G115: methodIdx from range is safe to convert to int32 for protobuf field indices
+		methodLoc := loc.child(serviceMethodField, int32(methodIdx))
+		bindings := r.getHTTPBindings(fqMethodName, method, methodLoc)
+		isUnbound := len(bindings) == 0
+		if isUnbound {
+			// No HTTP rule - post the whole request to a default route (backward compatibility)
+			url := "/" + serviceURLPart + "/" + method.GetName()
+			if r.IsFileToGenerate(fileName) {
+				r.reportUnboundMethod(methodLoc, strings.TrimPrefix(fqMethodName, "."), "POST "+url)
+			}
+			if r.UnboundMethods != UnboundMethodsGenerate {
+				isLeftOut = true
+				continue
+			}
+			rule := &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: url}, Body: "*"}
+			bindings = []*httpBinding{{rule: rule, loc: methodLoc}}
+		}
+
+		// the first rule is the primary binding, the others are additional bindings
+		for idx, binding := range bindings {
+			if !isUnbound && r.IsFileToGenerate(fileName) {
+				r.validateHTTPBinding(binding, strings.TrimPrefix(fqMethodName, "."), method)
+			}
 
-			// Create method for primary binding
 			methodData := createMethodFromRule(
 				method,
-				rule,
-				0, // bindingIndex = 0 for primary
-				serviceData,
+				binding.rule,
+				idx,
 				inputTypeFQName,
 				outputTypeFQName,
 				isInputTypeExternal,
 				isOutputTypeExternal,
 			)
-
-			fileData.TrackPackageNonScalarType(methodData.Input)
-			fileData.TrackPackageNonScalarType(methodData.Output)
-
-			serviceData.Methods = append(serviceData.Methods, methodData)
-
-			// Process additional bindings
-			for idx, additionalRule := range rule.GetAdditionalBindings() {
-				additionalMethodData := createMethodFromRule(
-					method,
-					additionalRule,
-					idx+1, // bindingIndex starts at 1 for additional bindings
-					serviceData,
-					inputTypeFQName,
-					outputTypeFQName,
-					isInputTypeExternal,
-					isOutputTypeExternal,
-				)
-
-				fileData.TrackPackageNonScalarType(additionalMethodData.Input)
-				fileData.TrackPackageNonScalarType(additionalMethodData.Output)
-
-				serviceData.Methods = append(serviceData.Methods, additionalMethodData)
-			}
-		} else {
-			// No HTTP annotation - use defaults (backward compatibility)
-			httpMethod := "POST"
-			url := "/" + serviceURLPart + "/" + method.GetName()
-			body := getHTTPBody(method)
-
-			methodData := &data.Method{
-				Name: method.GetName(),
-				URL:  url,
-				Input: &data.MethodArgument{
-					Type:       inputTypeFQName,
-					IsExternal: isInputTypeExternal,
-				},
-				Output: &data.MethodArgument{
-					Type:       outputTypeFQName,
-					IsExternal: isOutputTypeExternal,
-				},
-				ServerStreaming: method.GetServerStreaming(),
-				ClientStreaming: method.GetClientStreaming(),
-				HTTPMethod:      httpMethod,
-				HTTPRequestBody: body,
-				BindingIndex:    0,
-				TSMethodName:    method.GetName(),
+			methodData.Pagination = pagination
+			methodData.Output.Operation = operation
+			if nameLoc, ok := r.applyMethodOptions(fileName, fqMethodName, methodData, method, methodLoc, pathPrefix); ok {
+				named[methodData] = nameLoc
 			}
 
 			fileData.TrackPackageNonScalarType(methodData.Input)
@@ -241,7 +252,19 @@ func (r *Registry) analyseService(
 
 			serviceData.Methods = append(serviceData.Methods, methodData)
 		}
+		if names := methodOptions(method).GetBindingNames(); len(names) >= len(bindings) && r.IsFileToGenerate(fileName) {
+			r.report(SeverityWarning, methodLoc.child(methodOptionsField, tsMethodExtensionField, tsBindingNamesField),
+				"%s: binding_names has %d names for %d additional bindings", strings.TrimPrefix(fqMethodName, "."),
+				len(names), len(bindings)-1)
+		}
 	}
 
+	if isLeftOut && len(serviceData.Methods) == 0 {
+		// every method of the service is left out by the filters or for having no HTTP rule
+		return
+	}
+	r.nameAdditionalBindings(fileName, fqName, serviceData.Methods, named)
+	r.declareService(fileName, r.Types[fqName], serviceData,
+		loc.child(serviceOptionsField, tsServiceExtensionField, tsClientNameField))
 	fileData.Services = append(fileData.Services, serviceData)
 }
@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unboundService = `
+name: "library.proto"
+package: "library"
+message_type { name: "Book" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+service {
+  name: "Library"
+  method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+    options { [google.api.http] { get: "/v1/{name}" } } }
+  method { name: "SyncBooks" input_type: ".library.Book" output_type: ".library.Book" }
+}
+`
+
+func TestUnboundMethods(t *testing.T) {
+	tests := []struct {
+		unboundMethods string
+		wantMethods    []string
+		wantErrors     []string
+		wantWarnings   []string
+	}{
+		{
+			unboundMethods: UnboundMethodsGenerate,
+			wantMethods:    []string{"GetBook", "SyncBooks"},
+			wantWarnings: []string{"library.Library.SyncBooks has no HTTP rule and is called with " +
+				"POST /library.Library/SyncBooks, which the gateway only serves with generate_unbound_methods=true"},
+		},
+		{
+			unboundMethods: UnboundMethodsSkip,
+			wantMethods:    []string{"GetBook"},
+			wantWarnings:   []string{"library.Library.SyncBooks has no HTTP rule and is left out"},
+		},
+		{
+			unboundMethods: UnboundMethodsError,
+			wantErrors: []string{
+				"library.Library.SyncBooks has no HTTP rule, annotate it with google.api.http or set unbound_methods",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unboundMethods, func(t *testing.T) {
+			r, filesData, err := analyse(t, Options{UnboundMethods: tt.unboundMethods}, newRequest(t, unboundService))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Equal(t, tt.wantWarnings, warningMessages(r))
+			if err != nil {
+				return
+			}
+			methods := make([]string, 0)
+			for _, method := range filesData["library.proto"].Services[0].Methods {
+				methods = append(methods, method.TSMethodName)
+			}
+			assert.Equal(t, tt.wantMethods, methods)
+		})
+	}
+}
+
+func TestUnboundServiceSkipped(t *testing.T) {
+	req := newRequest(t, `
+name: "library.proto"
+package: "library"
+message_type { name: "Book" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+service {
+  name: "Library"
+  method { name: "SyncBooks" input_type: ".library.Book" output_type: ".library.Book" }
+}
+`)
+	r, filesData, err := analyse(t, Options{UnboundMethods: UnboundMethodsSkip}, req)
+	assert.NoError(t, err)
+	assert.Empty(t, filesData["library.proto"].Services)
+	assert.NotContains(t, r.declared["library.proto"], "LibraryClient")
+}
@@ -0,0 +1,509 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reservedWords are the identifiers which can't be declared by a generated file: the reserved words
+// of typescript, the names of its primitive types, the global types used unqualified by the generated
+// code, and the identifiers declared or imported by every generated file.
+var reservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"implements": true, "interface": true, "let": true, "package": true, "private": true,
+	"protected": true, "public": true, "static": true, "yield": true, "await": true, "arguments": true,
+	"eval": true, "any": true, "bigint": true, "boolean": true, "never": true, "number": true,
+	"object": true, "string": true, "symbol": true, "undefined": true, "unknown": true,
+	"Record": true, "Promise": true, "Partial": true, "Required": true, "Exclude": true, "NonNullable": true,
+	"JSON": true, "AsyncGenerator": true, "Uint8Array": true,
+	"fm": true, "z": true, "OneOf": true, "Absent": true, "StructPBValue": true,
+}
+
+// IsReservedWord returns whether a name can't be used as an identifier in the generated files.
+func IsReservedWord(name string) bool {
+	return reservedWords[name]
+}
+
+// symbol is a declaration of a generated file, at the top level or in a namespace.
+type symbol struct {
+	// kind describes the declaration in diagnostics, e.g. message
+	kind string
+	// fqName is the fully qualified name of the declaration
+	fqName string
+	// parents are the names of the elements the declaration is nested in, e.g. the messages of a
+	// nested message or the service of a method
+	parents []string
+	// parent is the fully qualified name of the message a nested message or enum is declared in
+	parent string
+	// localName is the name of the declaration inside its parents
+	localName string
+	// name is the identifier of the declaration
+	name string
+	// identifiers returns the identifiers declared along with the declaration for the given name of
+	// the declaration, helpers included
+	identifiers func(name string) []string
+	// resolve sets the identifier of the declaration and the namespace it is declared in
+	resolve func(name, namespace string)
+	// isMethod tells methods apart, they claim their identifiers after the types and services
+	isMethod bool
+	// option is the option the name of the declaration is set with, e.g. client_name, such names are
+	// never renamed and their collisions are reported as errors at nameLoc
+	option  string
+	nameLoc sourceLocation
+}
+
+// declare adds a declaration to the symbol table of a file.
+func (r *Registry) declare(fileName string, s *symbol) {
+	r.symbols[fileName] = append(r.symbols[fileName], s)
+}
+
+// declareMessage adds a message, its variants and helpers to the symbol table of a file, nameLoc is
+// the location of the name set with ts_message, if any.
+func (r *Registry) declareMessage(fileName string, parents []string, typeInfo *TypeInformation,
+	message *data.Message, nameLoc *sourceLocation) {
+	s := &symbol{
+		kind:      "message",
+		fqName:    typeInfo.FullyQualifiedName,
+		parents:   parents,
+		parent:    parentName(typeInfo, parents),
+		localName: typeInfo.LocalIdentifier,
+		name:      message.Name,
+		identifiers: func(name string) []string {
+			names := []string{name}
+			if r.hasFieldBehaviors(typeInfo.FullyQualifiedName, make(map[string]bool)) {
+				names = append(names, name+"Input", name+"Output")
+			}
+			if message.HasOneOfFields() {
+				for _, n := range names {
+					names = append(names, "Base"+n)
+				}
+				if r.GenerateTaggedOneOfs {
+					names = append(names, name+"Tagged", "to"+name+"Tagged", "from"+name+"Tagged")
+				}
+			}
+			if message.HasBytesFields() {
+				names = append(names, name+"JSON", "decode"+name)
+			}
+			names = append(names, r.validatorIdentifiers(name)...)
+			if r.GenerateConstraintValidators {
+				names = append(names, "validate"+name)
+			}
+			if r.GenerateFactories {
+				names = append(names, "create"+name, "with"+name+"Defaults")
+			}
+			if r.GenerateDescriptors {
+				names = append(names, name+"Descriptor")
+			}
+			if r.GenerateMessageUtils {
+				names = append(names, "equals"+name, "clone"+name, "merge"+name)
+			}
+			if r.GenerateUpdateMasks {
+				names = append(names, "fieldMaskOf"+name)
+			}
+			return names
+		},
+		resolve: func(name, namespace string) {
+			typeInfo.PackageIdentifier = qualifiedName(namespace, name)
+			typeInfo.Namespace = namespace
+			message.Name = name
+			message.Namespace = namespace
+		},
+	}
+	if nameLoc != nil {
+		s.option, s.nameLoc = "name", *nameLoc
+	}
+	r.declare(fileName, s)
+}
+
+// declareEnum adds an enum and its helpers to the symbol table of a file.
+func (r *Registry) declareEnum(fileName string, parents []string, typeInfo *TypeInformation, enum *data.Enum) {
+	r.declare(fileName, &symbol{
+		kind:      "enum",
+		fqName:    typeInfo.FullyQualifiedName,
+		parents:   parents,
+		parent:    parentName(typeInfo, parents),
+		localName: typeInfo.LocalIdentifier,
+		name:      enum.Name,
+		identifiers: func(name string) []string {
+			names := []string{name}
+			names = append(names, r.validatorIdentifiers(name)...)
+			if r.GenerateEnumHelpers {
+				names = append(names, name+"Values", "is"+name,
+					data.FunctionCase(name)+"FromNumber", data.FunctionCase(name)+"ToNumber")
+			}
+			if r.GenerateEnumLabels {
+				names = append(names, name+"Labels", name+"Options")
+			}
+			if r.GenerateDescriptors {
+				names = append(names, name+"Descriptor")
+			}
+			return names
+		},
+		resolve: func(name, namespace string) {
+			typeInfo.PackageIdentifier = qualifiedName(namespace, name)
+			typeInfo.Namespace = namespace
+			enum.Name = name
+			enum.Namespace = namespace
+		},
+	})
+}
+
+// declareResource adds the type and the helpers of the names of a resource to the symbol table of a
+// file.
+func (r *Registry) declareResource(fileName string, resource *data.Resource) {
+	r.declare(fileName, &symbol{
+		kind:      "resource",
+		fqName:    resource.Type,
+		localName: resource.Name,
+		name:      resource.Name,
+		identifiers: func(name string) []string {
+			return []string{name, name + "Parts"}
+		},
+		resolve: func(name, _ string) {
+			resource.Name = name
+		},
+	})
+}
+
+// declareService adds a service and its methods to the symbol table of a file. The methods are
+// declared as functions when the services are rendered as clients, and the class of the service is
+// declared on its own when it is named with ts_service.client_name, located at clientNameLoc.
+func (r *Registry) declareService(
+	fileName string, typeInfo *TypeInformation, service *data.Service, clientNameLoc sourceLocation) {
+	clientName := service.ClientName
+	className := func(name string) string {
+		if r.UseStaticClasses {
+			return name
+		}
+		return name + "Client"
+	}
+	r.declare(fileName, &symbol{
+		kind:      "service",
+		fqName:    typeInfo.FullyQualifiedName,
+		localName: service.Name,
+		name:      service.Name,
+		identifiers: func(name string) []string {
+			names := make([]string, 0)
+			if clientName == "" {
+				names = append(names, className(name))
+			}
+			if r.GenerateFakeServers {
+				names = append(names, name+"Server", "create"+name+"Router")
+			}
+			return names
+		},
+		resolve: func(name, _ string) {
+			typeInfo.PackageIdentifier = name
+			service.Name = name
+			if clientName == "" {
+				service.ClientName = className(name)
+			}
+		},
+	})
+	if clientName != "" {
+		r.declare(fileName, &symbol{
+			kind:      "client",
+			fqName:    typeInfo.FullyQualifiedName,
+			localName: clientName,
+			name:      clientName,
+			identifiers: func(name string) []string {
+				return []string{name}
+			},
+			resolve: func(name, _ string) {
+				service.ClientName = name
+			},
+			option:  "client_name",
+			nameLoc: clientNameLoc,
+		})
+	}
+
+	for _, method := range service.Methods {
+		r.declare(fileName, &symbol{
+			kind:      "method",
+			fqName:    typeInfo.FullyQualifiedName + "." + method.Name,
+			parents:   []string{service.Name},
+			localName: method.TSMethodName,
+			name:      method.TSMethodName,
+			identifiers: func(name string) []string {
+				names := make([]string, 0)
+				if !r.UseStaticClasses {
+					names = append(names, data.FunctionCase(name))
+					if r.GeneratePageIterators && method.Pagination != nil {
+						names = append(names, data.FunctionCase(name)+"Pages", data.FunctionCase(name)+"All")
+					}
+					if method.Output.Operation != nil {
+						names = append(names, "waitFor"+name)
+					}
+				}
+				if r.GenerateUpdateMasks && method.HTTPMethod == "PATCH" &&
+					method.HTTPRequestBody != nil && *method.HTTPRequestBody != "*" {
+					names = append(names, data.FunctionCase(name)+"UpdateMask")
+				}
+				return names
+			},
+			resolve: func(name, _ string) {
+				method.TSMethodName = name
+			},
+			isMethod: true,
+		})
+	}
+}
+
+// validatorIdentifiers returns the identifiers of the runtime validators of a message or an enum.
+func (r *Registry) validatorIdentifiers(name string) []string {
+	switch r.RuntimeValidators {
+	case RuntimeValidatorsStandalone:
+		return []string{"check" + name, "is" + name, "assert" + name}
+	case RuntimeValidatorsZod:
+		return []string{name + "Schema", "is" + name, "assert" + name}
+	default:
+		return nil
+	}
+}
+
+// hasFieldBehaviors returns whether a field annotated with google.api.field_behavior can be reached
+// from a message, in which case its Input and Output variants may be generated.
+func (r *Registry) hasFieldBehaviors(fqType string, visited map[string]bool) bool {
+	if visited[fqType] {
+		return false
+	}
+	visited[fqType] = true
+
+	typeInfo, ok := r.Types[fqType]
+	if !ok {
+		return false
+	}
+	if typeInfo.IsMapEntry {
+		return r.hasFieldBehaviors(typeInfo.ValueType.Type, visited)
+	}
+	if typeInfo.Message == nil {
+		return false
+	}
+	for _, f := range typeInfo.Message.Fields {
+		if f.IsOutputOnly || f.IsInputOnly || f.IsInputRequired || r.hasFieldBehaviors(f.Type, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSymbols renames the declarations whose identifiers collide with those of other declarations
+// of their file or with reserved words, then the module identifiers of the files which collide. The
+// outcome only depends on the files: declarations are resolved from the least nested to the most
+// nested in the order of the files, methods last, and a renamed declaration takes the first free name
+// out of its name joined to its parents with _, or followed by _ at the top level or in a namespace,
+// and then these followed by 2, 3, and so on. The identifiers of a namespace can't shadow those of
+// the namespaces enclosing it. Renames are reported as warnings for the files to generate, where the
+// names set with ts_message.name and ts_service.client_name which collide are errors instead.
+func (r *Registry) resolveSymbols(files []*descriptorpb.FileDescriptorProto) {
+	r.declared = make(map[string]map[string]*symbol)
+	for _, f := range files {
+		fileName := f.GetName()
+		symbols := r.symbols[fileName]
+		sort.SliceStable(symbols, func(i, j int) bool {
+			if symbols[i].isMethod != symbols[j].isMethod {
+				return !symbols[i].isMethod
+			}
+			return len(symbols[i].parents) < len(symbols[j].parents)
+		})
+
+		// scopes stores the identifiers declared in each namespace, namespaces stores the namespace of
+		// the types nested in each message
+		scopes := make(map[string]map[string]*symbol)
+		namespaces := make(map[string]string)
+		declared := make(map[string]*symbol)
+
+		// the types imported for ts_type can't be renamed, the declarations colliding with them are
+		scopes[""] = make(map[string]*symbol)
+		for _, imported := range r.typeImports[fileName] {
+			s := &symbol{kind: "the import for field", fqName: imported.field, name: imported.name}
+			scopes[""][imported.name] = s
+			declared[imported.name] = s
+		}
+
+		for _, s := range symbols {
+			namespace := ""
+			if r.NestedStyle == NestedStyleNamespace && s.parent != "" {
+				namespace = namespaces[s.parent]
+			}
+			visible := visibleScopes(scopes, namespace)
+
+			name := s.name
+			if other := findCollision(visible, s.identifiers(name)); other != "" {
+				if s.option != "" && r.IsFileToGenerate(fileName) {
+					r.report(SeverityError, s.nameLoc, "%s %q of %s %s can't be declared, %s",
+						s.option, name, s.kind, strings.TrimPrefix(s.fqName, "."), other)
+					continue
+				}
+				if named := findExplicitName(visible, s.identifiers(name)); named != nil && r.IsFileToGenerate(fileName) {
+					r.report(SeverityError, named.nameLoc, "%s %q of %s %s collides with %s %s",
+						named.option, named.name, named.kind, strings.TrimPrefix(named.fqName, "."),
+						s.kind, strings.TrimPrefix(s.fqName, "."))
+					continue
+				}
+				name = freeName(s, namespace, visible)
+				if r.IsFileToGenerate(fileName) {
+					r.report(SeverityWarning, sourceLocation{file: fileName}, "%s %s is generated as %s, %s",
+						s.kind, strings.TrimPrefix(s.fqName, "."), name, other)
+				}
+				s.name = name
+			}
+			s.resolve(name, namespace)
+			namespaces[s.fqName] = qualifiedName(namespace, name)
+
+			if scopes[namespace] == nil {
+				scopes[namespace] = make(map[string]*symbol)
+			}
+			for _, identifier := range s.identifiers(name) {
+				scopes[namespace][identifier] = s
+				if _, ok := declared[identifier]; !ok {
+					declared[identifier] = s
+				}
+			}
+		}
+		r.declared[fileName] = declared
+	}
+
+	r.resolveModuleIdentifiers(files)
+}
+
+// visibleScopes returns the identifiers declared in a namespace and in the namespaces enclosing it,
+// the top level included.
+func visibleScopes(scopes map[string]map[string]*symbol, namespace string) []map[string]*symbol {
+	visible := []map[string]*symbol{scopes[namespace]}
+	for namespace != "" {
+		namespace = namespace[:max(strings.LastIndex(namespace, "."), 0)]
+		visible = append(visible, scopes[namespace])
+	}
+	return visible
+}
+
+// findCollision describes why one of the identifiers can't be declared, or returns an empty string.
+func findCollision(visible []map[string]*symbol, identifiers []string) string {
+	for _, identifier := range identifiers {
+		if IsReservedWord(identifier) {
+			return fmt.Sprintf("%s is a reserved word", identifier)
+		}
+		for _, declared := range visible {
+			if other, ok := declared[identifier]; ok {
+				return fmt.Sprintf("%s is already declared by %s %s",
+					identifier, other.kind, strings.TrimPrefix(other.fqName, "."))
+			}
+		}
+	}
+	return ""
+}
+
+// findExplicitName returns the declaration named with an option which declares one of the
+// identifiers, or nil.
+func findExplicitName(visible []map[string]*symbol, identifiers []string) *symbol {
+	for _, identifier := range identifiers {
+		for _, declared := range visible {
+			if other, ok := declared[identifier]; ok && other.option != "" {
+				return other
+			}
+		}
+	}
+	return nil
+}
+
+// freeName returns the first name of a declaration whose identifiers don't collide.
+func freeName(s *symbol, namespace string, visible []map[string]*symbol) string {
+	base := s.name + "_"
+	if namespace == "" && len(s.parents) > 0 {
+		base = strings.Join(append(append([]string{}, s.parents...), s.localName), "_")
+	}
+	name := base
+	for n := 2; findCollision(visible, s.identifiers(name)) != ""; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	return name
+}
+
+// parentName returns the fully qualified name of the message a type is nested in, or an empty string
+// for top level types.
+func parentName(typeInfo *TypeInformation, parents []string) string {
+	if len(parents) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(typeInfo.FullyQualifiedName, "."+typeInfo.LocalIdentifier)
+}
+
+// qualifiedName returns a name prefixed with its namespace.
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// resolveModuleIdentifiers picks the identifier each file is imported as, which must differ from
+// the identifiers of the other files and from those declared by the files importing it.
+func (r *Registry) resolveModuleIdentifiers(files []*descriptorpb.FileDescriptorProto) {
+	importers := make(map[string][]string)
+	for _, f := range files {
+		for _, dependency := range f.GetDependency() {
+			importers[dependency] = append(importers[dependency], f.GetName())
+		}
+	}
+
+	sorted := make([]*descriptorpb.FileDescriptorProto, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GetName() < sorted[j].GetName() })
+
+	r.moduleIdentifiers = make(map[string]string)
+	taken := make(map[string]string)
+	for _, f := range sorted {
+		fileName := f.GetName()
+		base := data.GetModuleName(f.GetPackage(), fileName)
+		if moduleName, ok := r.moduleNames[fileName]; ok {
+			base = moduleName
+		}
+		collision := func(name string) string {
+			if other, ok := taken[name]; ok {
+				return fmt.Sprintf("%s is already the module identifier of %s", name, other)
+			}
+			for _, importer := range importers[fileName] {
+				if s, ok := r.declared[importer][name]; ok {
+					return fmt.Sprintf("%s is already declared by %s %s in %s",
+						name, s.kind, strings.TrimPrefix(s.fqName, "."), importer)
+				}
+			}
+			return ""
+		}
+
+		name := base
+		if other := collision(name); other != "" {
+			name = base + "_"
+			for n := 2; collision(name) != ""; n++ {
+				name = fmt.Sprintf("%s_%d", base, n)
+			}
+			for _, importer := range importers[fileName] {
+				if r.IsFileToGenerate(importer) {
+					r.report(SeverityWarning, sourceLocation{file: importer}, "%s is imported as %s, %s",
+						fileName, name, other)
+					break
+				}
+			}
+		}
+		taken[name] = fileName
+		r.moduleIdentifiers[fileName] = name
+	}
+}
+
+// ModuleIdentifier returns the identifier a file is imported as in the files depending on it.
+func (r *Registry) ModuleIdentifier(packageName, fileName string) string {
+	if name, ok := r.moduleIdentifiers[fileName]; ok {
+		return name
+	}
+	return data.GetModuleName(packageName, fileName)
+}
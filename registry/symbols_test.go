@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSymbols(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		wantNames    map[string]string
+		wantWarnings []string
+	}{
+		{
+			name: "global type",
+			file: `name: "records.proto" package: "records"
+			  message_type { name: "Record" field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+			  message_type { name: "Promise" }
+			  message_type { name: "Ledger"
+			    field { name: "records" number: 1 type: TYPE_MESSAGE type_name: ".records.Record" label: LABEL_REPEATED } }`,
+			wantNames: map[string]string{".records.Record": "Record_", ".records.Promise": "Promise_", ".records.Ledger": "Ledger"},
+			wantWarnings: []string{
+				"message records.Record is generated as Record_, Record is a reserved word",
+				"message records.Promise is generated as Promise_, Promise is a reserved word",
+			},
+		},
+		{
+			name: "nested types",
+			file: `name: "records.proto" package: "records"
+			  message_type { name: "Foo" nested_type { name: "BarBaz" } }
+			  message_type { name: "FooBar" nested_type { name: "Baz" } }`,
+			wantNames: map[string]string{".records.Foo.BarBaz": "FooBarBaz", ".records.FooBar.Baz": "FooBar_Baz"},
+			wantWarnings: []string{
+				"message records.FooBar.Baz is generated as FooBar_Baz, FooBarBaz is already declared by message " +
+					"records.Foo.BarBaz",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, err := analyse(t, Options{}, newRequest(t, tt.file))
+			require.NoError(t, err)
+			for fqName, want := range tt.wantNames {
+				assert.Equal(t, want, r.Types[fqName].PackageIdentifier, fqName)
+			}
+			assert.Equal(t, tt.wantWarnings, warningMessages(r))
+		})
+	}
+}
+
+func TestExplicitNameCollisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantErrors []string
+	}{
+		{
+			name: "message name",
+			file: `name: "shelf.proto" package: "shelf"
+			  message_type { name: "Shelf" }
+			  message_type { name: "Box" options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { name: "Shelf" } } }`,
+			wantErrors: []string{`name "Shelf" of message shelf.Box can't be declared, Shelf is already declared by message shelf.Shelf`},
+		},
+		{
+			name: "message name of a helper",
+			file: `name: "shelf.proto" package: "shelf"
+			  message_type { name: "Shelf" }
+			  message_type { name: "Box" options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { name: "ShelfSchema" } } }`,
+			wantErrors: []string{
+				`name "ShelfSchema" of message shelf.Box can't be declared, ShelfSchema is already declared by message shelf.Shelf`,
+			},
+		},
+		{
+			name: "client name",
+			file: `name: "shelf.proto" package: "shelf"
+			  message_type { name: "Shelf" }
+			  service { name: "Shelves" options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_service] { client_name: "Shelf" } }
+			    method { name: "GetShelf" input_type: ".shelf.Shelf" output_type: ".shelf.Shelf"
+			      options { [google.api.http] { get: "/v1/shelf" } } } }`,
+			wantErrors: []string{`client_name "Shelf" of client shelf.Shelves can't be declared, Shelf is already declared by message shelf.Shelf`},
+		},
+		{
+			// the error is reported at the option wherever the other declaration is
+			name: "message name declared first",
+			file: `name: "shelf.proto" package: "shelf"
+			  message_type { name: "Box" options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { name: "Crate" } } }
+			  message_type { name: "Crate" }`,
+			wantErrors: []string{`name "Crate" of message shelf.Box collides with message shelf.Crate`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, err := analyse(t, Options{RuntimeValidators: RuntimeValidatorsZod}, newRequest(t, tt.file))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Empty(t, warningMessages(r))
+		})
+	}
+}
+
+func TestExplicitNameLocation(t *testing.T) {
+	// the symbols are resolved once every file is analysed, the error is located in its own file
+	req := newRequest(t, `name: "shelf.proto" package: "shelf"
+	  message_type { name: "Shelf" }
+	  message_type { name: "Box" options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { name: "Shelf" } } }
+	  source_code_info { location { path: [4, 1, 7, 50007, 2] span: [3, 4, 20] } }`,
+		`name: "book.proto" package: "book"
+	  message_type { name: "Book" }
+	  source_code_info { location { path: [4, 0] span: [7, 0, 20] } }`)
+	_, _, err := analyse(t, Options{}, req)
+	var diagnostics Diagnostics
+	require.ErrorAs(t, err, &diagnostics)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, `shelf.proto:4:5: name "Shelf" of message shelf.Box can't be declared, `+
+		`Shelf is already declared by message shelf.Shelf`, diagnostics[0].String())
+}
@@ -0,0 +1,270 @@
+package registry
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/options"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Numbers of the fields leading to the options of options/ts_package.proto, and to the types used by
+// fields and methods, in the paths of the source code info.
+const (
+	fileOptionsField          = 8
+	messageOptionsField       = 7
+	serviceOptionsField       = 3
+	tsFileExtensionField      = 50006
+	tsMessageExtensionField   = 50007
+	tsServiceExtensionField   = 50008
+	tsMethodExtensionField    = 50009
+	tsOutputPathField         = 1
+	tsModuleNameField         = 2
+	tsMessageNameField        = 2
+	tsMessageDeclarationField = 4
+	tsClientNameField         = 1
+	tsPathPrefixField         = 2
+	tsMethodNameField         = 1
+	tsBindingNamesField       = 2
+	tsStreamingStyleField     = 4
+	fieldTypeNameField        = 6
+	methodInputTypeField      = 2
+	methodOutputTypeField     = 3
+)
+
+// Values of data.Method.StreamingStyle.
+const (
+	StreamingStyleCallback      = "callback"
+	StreamingStyleAsyncIterable = "async_iterable"
+)
+
+// identifierRegexp matches the TypeScript identifiers the options can declare.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// The getters of the options return the default values when the options aren't set.
+
+func fileOptions(f *descriptorpb.FileDescriptorProto) *options.TSFileOptions {
+	opts, _ := proto.GetExtension(f.GetOptions(), options.E_TsFile).(*options.TSFileOptions)
+	return opts
+}
+
+func messageOptions(message *descriptorpb.DescriptorProto) *options.TSMessageOptions {
+	opts, _ := proto.GetExtension(message.GetOptions(), options.E_TsMessage).(*options.TSMessageOptions)
+	return opts
+}
+
+func serviceOptions(service *descriptorpb.ServiceDescriptorProto) *options.TSServiceOptions {
+	opts, _ := proto.GetExtension(service.GetOptions(), options.E_TsService).(*options.TSServiceOptions)
+	return opts
+}
+
+func methodOptions(method *descriptorpb.MethodDescriptorProto) *options.TSMethodOptions {
+	opts, _ := proto.GetExtension(method.GetOptions(), options.E_TsMethod).(*options.TSMethodOptions)
+	return opts
+}
+
+// validIdentifier returns whether a name set with an option can be declared, or reports an error for
+// the files to generate.
+func (r *Registry) validIdentifier(fileName string, loc sourceLocation, option, name string) bool {
+	switch {
+	case !identifierRegexp.MatchString(name):
+		if r.IsFileToGenerate(fileName) {
+			r.report(SeverityError, loc, "%s %q isn't a valid identifier", option, name)
+		}
+		return false
+	case IsReservedWord(name):
+		if r.IsFileToGenerate(fileName) {
+			r.report(SeverityError, loc, "%s %q is a reserved word", option, name)
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// analyseFileOptions reads the ts_file options of a file, the output path and the module name are
+// used when the file is imported by other files.
+func (r *Registry) analyseFileOptions(f *descriptorpb.FileDescriptorProto) {
+	fileName := f.GetName()
+	opts := fileOptions(f)
+	loc := sourceLocation{file: fileName, path: []int32{fileOptionsField, tsFileExtensionField}}
+
+	if outputPath := opts.GetOutputPath(); outputPath != "" {
+		cleaned := path.Clean(outputPath)
+		switch {
+		case !strings.HasSuffix(cleaned, ".ts"):
+			if r.IsFileToGenerate(fileName) {
+				r.report(SeverityError, loc.child(tsOutputPathField), "output_path %q must end with .ts", outputPath)
+			}
+		case path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../"):
+			if r.IsFileToGenerate(fileName) {
+				r.report(SeverityError, loc.child(tsOutputPathField),
+					"output_path %q must be relative to the output directory", outputPath)
+			}
+		default:
+			r.outputPaths[fileName] = cleaned
+		}
+	}
+
+	if moduleName := opts.GetModuleName(); moduleName != "" {
+		if r.validIdentifier(fileName, loc.child(tsModuleNameField), "module_name", moduleName) {
+			r.moduleNames[fileName] = moduleName
+		}
+	}
+}
+
+// getTSFileName returns the name of the file generated for a proto file.
+func (r *Registry) getTSFileName(fileName string) string {
+	if outputPath, ok := r.outputPaths[fileName]; ok {
+		return outputPath
+	}
+	return data.GetTSFileName(fileName)
+}
+
+// checkSkippedMessages reports the fields and methods of the files to generate which use a message
+// left out with ts_message.skip, at the type of the field or of the request or response.
+func (r *Registry) checkSkippedMessages(files []*descriptorpb.FileDescriptorProto, filesData map[string]*data.File) {
+	// skippedType returns the skipped message used by a type, looking through the entries of maps
+	skippedType := func(fqType string) (string, bool) {
+		typeInfo, ok := r.Types[fqType]
+		if ok && typeInfo.IsMapEntry && typeInfo.ValueType != nil {
+			fqType = typeInfo.ValueType.Type
+			typeInfo, ok = r.Types[fqType]
+		}
+		return strings.TrimPrefix(fqType, "."), ok && typeInfo.IsSkipped
+	}
+	for _, f := range files {
+		fileName := f.GetName()
+		fileData, ok := filesData[fileName]
+		if !ok || !r.IsFileToGenerate(fileName) {
+			continue
+		}
+		locations := elementLocations(f)
+		for _, message := range fileData.Messages {
+			for _, field := range message.Fields {
+				if skipped, ok := skippedType(field.Type); ok {
+					loc := locations[message.FQType+"."+field.Name].child(fieldTypeNameField)
+					r.report(SeverityError, loc, "%s.%s uses %s, which is left out with ts_message.skip",
+						strings.TrimPrefix(message.FQType, "."), field.Name, skipped)
+				}
+			}
+		}
+		for _, service := range fileData.Services {
+			fqServiceName := strings.TrimPrefix(f.GetPackage()+"."+service.Name, ".")
+			for _, method := range service.Methods {
+				if method.BindingIndex > 0 {
+					// the additional bindings share the messages of the primary binding
+					continue
+				}
+				methodLoc := locations["."+fqServiceName+"."+method.Name]
+				typeFields := []int32{methodInputTypeField, methodOutputTypeField}
+				for idx, fqType := range []string{method.Input.Type, method.Output.Type} {
+					if skipped, ok := skippedType(fqType); ok {
+						r.report(SeverityError, methodLoc.child(typeFields[idx]),
+							"%s.%s uses %s, which is left out with ts_message.skip", fqServiceName, method.Name, skipped)
+					}
+				}
+			}
+		}
+	}
+}
+
+// elementLocations returns the locations of the fields and the methods of a file keyed by their fully
+// qualified name, e.g. .library.Book.title and .library.Library.GetBook.
+func elementLocations(f *descriptorpb.FileDescriptorProto) map[string]sourceLocation {
+	locations := make(map[string]sourceLocation)
+	prefix := ""
+	if f.GetPackage() != "" {
+		prefix = "." + f.GetPackage()
+	}
+	var addMessage func(fqName string, loc sourceLocation, message *descriptorpb.DescriptorProto)
+	addMessage = func(fqName string, loc sourceLocation, message *descriptorpb.DescriptorProto) {
+		for idx, field := range message.GetField() {
+			//nolint:gosec // G115: small index
+			locations[fqName+"."+field.GetName()] = loc.child(messageFieldField, int32(idx))
+		}
+		for idx, nested := range message.GetNestedType() {
+			//nolint:gosec // G115: small index
+			addMessage(fqName+"."+nested.GetName(), loc.child(messageNestedField, int32(idx)), nested)
+		}
+	}
+	for idx, message := range f.GetMessageType() {
+		//nolint:gosec // G115: small index
+		loc := sourceLocation{file: f.GetName(), path: []int32{fileMessageField, int32(idx)}}
+		addMessage(prefix+"."+message.GetName(), loc, message)
+	}
+	for serviceIdx, service := range f.GetService() {
+		for idx, method := range service.GetMethod() {
+			//nolint:gosec // G115: small indexes
+			locations[prefix+"."+service.GetName()+"."+method.GetName()] = sourceLocation{
+				file: f.GetName(),
+				path: []int32{fileServiceField, int32(serviceIdx), serviceMethodField, int32(idx)},
+			}
+		}
+	}
+	return locations
+}
+
+// analyseServiceOptions reads the ts_service options of a service.
+func (r *Registry) analyseServiceOptions(
+	fileName string, serviceData *data.Service, service *descriptorpb.ServiceDescriptorProto,
+	loc sourceLocation) (pathPrefix string) {
+	opts := serviceOptions(service)
+	loc = loc.child(serviceOptionsField, tsServiceExtensionField)
+	if clientName := opts.GetClientName(); clientName != "" &&
+		r.validIdentifier(fileName, loc.child(tsClientNameField), "client_name", clientName) {
+		serviceData.ClientName = clientName
+	}
+	pathPrefix = opts.GetPathPrefix()
+	if pathPrefix != "" && (!strings.HasPrefix(pathPrefix, "/") || strings.HasSuffix(pathPrefix, "/")) {
+		if r.IsFileToGenerate(fileName) {
+			r.report(SeverityError, loc.child(tsPathPrefixField),
+				"path_prefix %q must start with / and can't end with /", pathPrefix)
+		}
+		return ""
+	}
+	return pathPrefix
+}
+
+// applyMethodOptions sets the name, the streaming style and the path prefix of a binding of a method
+// from the ts_method options of the method, it returns the location of the name of the binding when
+// it is named by the options.
+func (r *Registry) applyMethodOptions(
+	fileName, fqMethodName string, methodData *data.Method, method *descriptorpb.MethodDescriptorProto,
+	loc sourceLocation, pathPrefix string) (sourceLocation, bool) {
+	opts := methodOptions(method)
+	loc = loc.child(methodOptionsField, tsMethodExtensionField)
+	methodData.PathPrefix = pathPrefix
+
+	style := opts.GetStreamingStyle()
+	switch {
+	case !method.GetServerStreaming():
+		if style != options.StreamingStyle_STREAMING_STYLE_UNSPECIFIED && methodData.BindingIndex == 0 &&
+			r.IsFileToGenerate(fileName) {
+			r.report(SeverityWarning, loc.child(tsStreamingStyleField),
+				"%s: streaming_style is ignored, the method isn't server streaming", strings.TrimPrefix(fqMethodName, "."))
+		}
+	case style == options.StreamingStyle_STREAMING_STYLE_ASYNC_ITERABLE:
+		methodData.StreamingStyle = StreamingStyleAsyncIterable
+		r.streamingIterators = r.streamingIterators || r.IsFileToGenerate(fileName)
+	default:
+		methodData.StreamingStyle = StreamingStyleCallback
+	}
+
+	name, nameLoc := opts.GetName(), loc.child(tsMethodNameField)
+	if methodData.BindingIndex > 0 {
+		name = ""
+		if names := opts.GetBindingNames(); methodData.BindingIndex <= len(names) {
+			//nolint:gosec // G115: the binding index is bounded by the number of names
+			name, nameLoc = names[methodData.BindingIndex-1], loc.child(tsBindingNamesField, int32(methodData.BindingIndex-1))
+		}
+	}
+	if name == "" || !r.validIdentifier(fileName, nameLoc, "name", name) {
+		return sourceLocation{}, false
+	}
+	methodData.TSMethodName = name
+	return nameLoc, true
+}
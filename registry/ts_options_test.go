@@ -0,0 +1,355 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shelfOptionsFile is imported by bookOptionsFile, its ts_file options are appended by the tests.
+const shelfOptionsFile = `
+name: "shelf.proto"
+package: "shelf"
+message_type { name: "Shelf" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+`
+
+const bookOptionsFile = `
+name: "book.proto"
+package: "book"
+dependency: "shelf.proto"
+message_type {
+  name: "Book"
+  field { name: "shelf" number: 1 type: TYPE_MESSAGE type_name: ".shelf.Shelf" label: LABEL_OPTIONAL }
+}
+`
+
+func TestFileOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        string
+		wantTSFileName string
+		wantSourceFile string
+		wantModuleName string
+		wantErrors     []string
+	}{
+		{
+			name:           "no options",
+			wantTSFileName: "shelf.pb.ts",
+			wantSourceFile: "./shelf.pb",
+			wantModuleName: "ShelfShelf",
+		},
+		{
+			name:           "output path",
+			options:        `output_path: "api/./shelves.ts"`,
+			wantTSFileName: "api/shelves.ts",
+			wantSourceFile: "./api/shelves",
+			// the module identifier is still derived from the name of the proto file
+			wantModuleName: "ShelfShelf",
+		},
+		{
+			name:       "output path without .ts",
+			options:    `output_path: "api/shelves.js"`,
+			wantErrors: []string{`output_path "api/shelves.js" must end with .ts`},
+		},
+		{
+			name:       "output path out of the output directory",
+			options:    `output_path: "api/../../shelves.ts"`,
+			wantErrors: []string{`output_path "api/../../shelves.ts" must be relative to the output directory`},
+		},
+		{
+			name:       "absolute output path",
+			options:    `output_path: "/api/shelves.ts"`,
+			wantErrors: []string{`output_path "/api/shelves.ts" must be relative to the output directory`},
+		},
+		{
+			name:           "module name",
+			options:        `module_name: "ShelfApi"`,
+			wantTSFileName: "shelf.pb.ts",
+			wantSourceFile: "./shelf.pb",
+			wantModuleName: "ShelfApi",
+		},
+		{
+			name:       "invalid module name",
+			options:    `module_name: "shelf-api"`,
+			wantErrors: []string{`module_name "shelf-api" isn't a valid identifier`},
+		},
+		{
+			name:       "reserved module name",
+			options:    `module_name: "fm"`,
+			wantErrors: []string{`module_name "fm" is a reserved word`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shelf := shelfOptionsFile
+			if tt.options != "" {
+				shelf += `options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_file] { ` + tt.options + ` } }`
+			}
+			r, filesData, err := analyse(t, Options{}, newRequest(t, shelf, bookOptionsFile))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Empty(t, warningMessages(r))
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantTSFileName, filesData["shelf.proto"].TSFileName)
+			dependencies := filesData["book.proto"].Dependencies()
+			require.Len(t, dependencies, 1)
+			assert.Equal(t, tt.wantSourceFile, dependencies[0].SourceFile)
+			assert.Equal(t, tt.wantModuleName, dependencies[0].ModuleIdentifier)
+		})
+	}
+}
+
+func TestFileOptionsOfImportedFiles(t *testing.T) {
+	// the options of the files which aren't generated are used by the files importing them, but their
+	// problems are only reported when the files are generated
+	req := newRequest(t, shelfOptionsFile+
+		`options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_file] { output_path: "shelves.js" module_name: "ShelfApi" } }`,
+		bookOptionsFile)
+	req.FileToGenerate = []string{"book.proto"}
+	_, filesData, err := analyse(t, Options{}, req)
+	require.NoError(t, err)
+	dependencies := filesData["book.proto"].Dependencies()
+	require.Len(t, dependencies, 1)
+	assert.Equal(t, "./shelf.pb", dependencies[0].SourceFile)
+	assert.Equal(t, "ShelfApi", dependencies[0].ModuleIdentifier)
+}
+
+// replaceOnce replaces the first occurrence of a string which must be found.
+func replaceOnce(t *testing.T, s, old, replacement string) string {
+	t.Helper()
+	require.Contains(t, s, old)
+	return strings.Replace(s, old, replacement, 1)
+}
+
+const messageOptionsFile = `
+name: "library.proto"
+package: "library"
+message_type {
+  name: "Book"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+  field { name: "isbn" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL oneof_index: 0 }
+  field { name: "issn" number: 3 type: TYPE_STRING label: LABEL_OPTIONAL oneof_index: 0 }
+  oneof_decl { name: "id" }
+}
+message_type {
+  name: "Draft"
+  field { name: "book" number: 1 type: TYPE_MESSAGE type_name: ".library.Book" label: LABEL_OPTIONAL }
+  nested_type { name: "Note" field { name: "text" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+}
+`
+
+func TestMessageOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		bookOptions    string
+		draftOptions   string
+		wantMessages   []string
+		wantInterfaces []string
+		wantErrors     []string
+	}{
+		{
+			name:         "no options",
+			wantMessages: []string{"Book", "Draft", "DraftNote"},
+		},
+		{
+			// the nested types of a skipped message are still generated
+			name:         "skip",
+			draftOptions: `skip: true`,
+			wantMessages: []string{"Book", "DraftNote"},
+		},
+		{
+			name:        "skip a used message",
+			bookOptions: `skip: true`,
+			wantErrors:  []string{"library.Draft.book uses library.Book, which is left out with ts_message.skip"},
+		},
+		{
+			name:           "interface",
+			draftOptions:   `declaration: DECLARATION_INTERFACE`,
+			wantMessages:   []string{"Book", "Draft", "DraftNote"},
+			wantInterfaces: []string{"Draft"},
+		},
+		{
+			name:        "interface with oneofs",
+			bookOptions: `declaration: DECLARATION_INTERFACE`,
+			wantErrors:  []string{"library.Book: messages with oneofs can't be declared as interfaces"},
+		},
+		{
+			name:         "name",
+			draftOptions: `name: "Manuscript"`,
+			wantMessages: []string{"Book", "DraftNote", "Manuscript"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := messageOptionsFile
+			if tt.bookOptions != "" {
+				file = replaceOnce(t, file, `oneof_decl { name: "id" }`,
+					`oneof_decl { name: "id" } options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { `+
+						tt.bookOptions+` } }`)
+			}
+			if tt.draftOptions != "" {
+				file = replaceOnce(t, file, `nested_type { name: "Note"`,
+					`options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { `+tt.draftOptions+
+						` } } nested_type { name: "Note"`)
+			}
+			r, filesData, err := analyse(t, Options{}, newRequest(t, file))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Empty(t, warningMessages(r))
+			if err != nil {
+				return
+			}
+			messages := make([]string, 0)
+			var interfaces []string
+			for _, message := range filesData["library.proto"].Messages {
+				messages = append(messages, message.Name)
+				if message.IsInterface {
+					interfaces = append(interfaces, message.Name)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantMessages, messages)
+			assert.Equal(t, tt.wantInterfaces, interfaces)
+		})
+	}
+}
+
+func TestSkippedMessageLocations(t *testing.T) {
+	req := newRequest(t, `
+name: "library.proto"
+package: "library"
+message_type {
+  name: "Book"
+  options { [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_message] { skip: true } }
+}
+message_type {
+  name: "Shelf"
+  nested_type {
+    name: "Slot"
+    field { name: "book" number: 1 type: TYPE_MESSAGE type_name: ".library.Book" label: LABEL_OPTIONAL }
+  }
+}
+service {
+  name: "Library"
+  method { name: "GetBook" input_type: ".library.Shelf" output_type: ".library.Book"
+    options { [google.api.http] { get: "/v1/books" } } }
+}
+source_code_info {
+  location { path: [4, 1, 3, 0, 2, 0] span: [10, 4, 60] }
+  location { path: [4, 1, 3, 0, 2, 0, 6] span: [10, 13, 25] }
+  location { path: [6, 0, 2, 0] span: [15, 2, 60] }
+  location { path: [6, 0, 2, 0, 3] span: [15, 40, 52] }
+}
+`)
+	_, _, err := analyse(t, Options{}, req)
+	var diagnostics Diagnostics
+	require.ErrorAs(t, err, &diagnostics)
+	messages := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.String())
+	}
+	assert.Equal(t, []string{
+		"library.proto:11:14: library.Shelf.Slot.book uses library.Book, which is left out with ts_message.skip",
+		"library.proto:16:41: library.Library.GetBook uses library.Book, which is left out with ts_message.skip",
+	}, messages)
+}
+
+const methodOptionsFile = `
+name: "library.proto"
+package: "library"
+message_type { name: "Book" field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL } }
+`
+
+func TestMethodOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		methods       string
+		wantMethods   []string
+		wantIterators bool
+		wantErrors    []string
+		wantWarnings  []string
+	}{
+		{
+			name: "binding names",
+			methods: `method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			  options {
+			    [google.api.http] { get: "/v1/{name}" additional_bindings { get: "/v2/{name}" } additional_bindings { post: "/v2/{name}" } }
+			    [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method] { name: "fetchBook" binding_names: "fetchBookV2" }
+			  } }`,
+			wantMethods: []string{"fetchBook GET", "fetchBookV2 GET", "GetBookPost POST"},
+		},
+		{
+			name: "too many binding names",
+			methods: `method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			  options {
+			    [google.api.http] { get: "/v1/{name}" additional_bindings { get: "/v2/{name}" } }
+			    [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method] { binding_names: "GetBookV2" binding_names: "GetBookV3" }
+			  } }`,
+			wantMethods:  []string{"GetBook GET", "GetBookV2 GET"},
+			wantWarnings: []string{"library.Library.GetBook: binding_names has 2 names for 1 additional bindings"},
+		},
+		{
+			name: "binding name used by another method",
+			methods: `method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			  options {
+			    [google.api.http] { get: "/v1/{name}" additional_bindings { get: "/v2/{name}" } }
+			    [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method] { binding_names: "ListBooks" }
+			  } }
+			  method { name: "ListBooks" input_type: ".library.Book" output_type: ".library.Book"
+			    options { [google.api.http] { get: "/v1/books" } } }`,
+			wantErrors: []string{"library.Library.GetBook: the name ListBooks is already used by another method of the service"},
+			wantWarnings: []string{"method library.Library.ListBooks is generated as Library_ListBooks, " +
+				"listBooks is already declared by method library.Library.GetBook"},
+		},
+		{
+			name: "skip",
+			methods: `method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			  options { [google.api.http] { get: "/v1/{name}" } } }
+			  method { name: "DeleteBook" input_type: ".library.Book" output_type: ".library.Book"
+			  options {
+			    [google.api.http] { delete: "/v1/{name}" }
+			    [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method] { skip: true }
+			  } }`,
+			wantMethods: []string{"GetBook GET"},
+		},
+		{
+			name: "async iterable",
+			methods: `method { name: "WatchBooks" input_type: ".library.Book" output_type: ".library.Book" server_streaming: true
+			  options {
+			    [google.api.http] { get: "/v1/books:watch" }
+			    [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method] { streaming_style: STREAMING_STYLE_ASYNC_ITERABLE }
+			  } }`,
+			wantMethods:   []string{"WatchBooks GET async_iterable"},
+			wantIterators: true,
+		},
+		{
+			name: "streaming style of a unary method",
+			methods: `method { name: "GetBook" input_type: ".library.Book" output_type: ".library.Book"
+			  options {
+			    [google.api.http] { get: "/v1/{name}" additional_bindings { get: "/v2/{name}" } }
+			    [grpc.gateway.protoc_gen_grpc_gateway_ts.options.ts_method] { streaming_style: STREAMING_STYLE_ASYNC_ITERABLE }
+			  } }`,
+			wantMethods:  []string{"GetBook GET", "GetBookGet GET"},
+			wantWarnings: []string{"library.Library.GetBook: streaming_style is ignored, the method isn't server streaming"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := methodOptionsFile + `service { name: "Library" ` + tt.methods + ` }`
+			r, filesData, err := analyse(t, Options{}, newRequest(t, file))
+			assert.Equal(t, tt.wantErrors, diagnosticMessages(t, err))
+			assert.Equal(t, tt.wantWarnings, warningMessages(r))
+			if err != nil {
+				return
+			}
+			methods := make([]string, 0)
+			for _, method := range filesData["library.proto"].Services[0].Methods {
+				// the streaming style is only set for server streaming methods
+				methods = append(methods, strings.TrimSpace(method.TSMethodName+" "+method.HTTPMethod+" "+method.StreamingStyle))
+			}
+			assert.Equal(t, tt.wantMethods, methods)
+			assert.Equal(t, tt.wantIterators, r.UsesStreamingIterators())
+		})
+	}
+}
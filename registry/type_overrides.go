@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/data"
+	"github.com/dpup/protoc-gen-grpc-gateway-ts/options"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Numbers of the fields leading to the fields of a message in the paths of the source code info.
+const (
+	fileMessageField     = 4
+	messageFieldField    = 2
+	messageNestedField   = 3
+	fieldOptionsField    = 8
+	tsTypeExtensionField = 50004
+)
+
+// leadingIdentifierRegexp matches the identifier a TypeScript type starts with, e.g. Ids in Ids.UserId.
+var leadingIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*`)
+
+// typeImport is a type imported by a generated file for the ts_type option of its fields.
+type typeImport struct {
+	name   string
+	module string
+	// field is the fully qualified name of the first field the type is imported for
+	field string
+}
+
+// wellKnownOverridableTypes are the messages encoded as JSON strings or values rather than objects
+// of their fields, which ts_type can be used on.
+var wellKnownOverridableTypes = map[string]bool{
+	".google.protobuf.Timestamp": true,
+	".google.protobuf.Duration":  true,
+	".google.protobuf.FieldMask": true,
+	".google.protobuf.Struct":    true,
+	".google.protobuf.Value":     true,
+	".google.protobuf.ListValue": true,
+}
+
+// analyseFieldTypeOverride reads the ts_type and ts_import options of a field, which must be
+// compatible with the JSON encoding of the field.
+func (r *Registry) analyseFieldTypeOverride(
+	fileName string, fieldData *data.Field, f *descriptorpb.FieldDescriptorProto, loc sourceLocation) {
+	opts := f.GetOptions()
+	if opts == nil {
+		return
+	}
+	tsType, _ := proto.GetExtension(opts, options.E_TsType).(string)
+	module, _ := proto.GetExtension(opts, options.E_TsImport).(string)
+	tsType = strings.TrimSpace(tsType)
+	if tsType == "" && module == "" {
+		return
+	}
+
+	fqFieldName := strings.TrimPrefix(fieldData.Message.FQType, ".") + "." + f.GetName()
+	loc = loc.child(fieldOptionsField, tsTypeExtensionField)
+	report := func(format string, args ...interface{}) {
+		if r.IsFileToGenerate(fileName) {
+			r.report(SeverityError, loc, format, args...)
+		}
+	}
+	if tsType == "" {
+		report("%s: ts_import requires ts_type", fqFieldName)
+		return
+	}
+	if reason := r.typeOverrideConflict(f); reason != "" {
+		report("%s: ts_type can't be used on %s", fqFieldName, reason)
+		return
+	}
+
+	if module != "" {
+		name := leadingIdentifierRegexp.FindString(tsType)
+		if name == "" {
+			report("%s: ts_type %q must start with the name of the type imported from %s", fqFieldName, tsType, module)
+			return
+		}
+		if other := r.addTypeImport(fileName, typeImport{name: name, module: module, field: fqFieldName}); other != nil {
+			report("%s: %s is already imported from %s for %s", fqFieldName, name, other.module, other.field)
+			return
+		}
+	}
+	fieldData.TSType = tsType
+}
+
+// typeOverrideConflict describes the fields whose JSON encoding can't be typed with ts_type, or
+// returns an empty string.
+func (r *Registry) typeOverrideConflict(f *descriptorpb.FieldDescriptorProto) string {
+	switch f.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "bytes fields, which are decoded to Uint8Array"
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return "enum fields, which are typed by their enum"
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		if typeInfo, ok := r.Types[f.GetTypeName()]; ok && typeInfo.IsMapEntry {
+			return "map fields"
+		}
+		if !wellKnownOverridableTypes[f.GetTypeName()] {
+			return "message fields other than google.protobuf.Timestamp, Duration, FieldMask, Struct, Value " +
+				"and ListValue, which are encoded as objects of their fields"
+		}
+	default:
+	}
+	return ""
+}
+
+// addTypeImport records a type imported by a file, it returns the import of another type of the same
+// name from a different module.
+func (r *Registry) addTypeImport(fileName string, imported typeImport) *typeImport {
+	for _, other := range r.typeImports[fileName] {
+		if other.name == imported.name {
+			if other.module != imported.module {
+				return other
+			}
+			return nil
+		}
+	}
+	r.typeImports[fileName] = append(r.typeImports[fileName], &imported)
+	return nil
+}
+
+// addTypeImportDependencies adds the imports of the types of the ts_type option to the dependencies
+// of a file, one per module.
+func (r *Registry) addTypeImportDependencies(fileData *data.File) {
+	modules := make(map[string]*data.Dependency)
+	for _, imported := range r.typeImports[fileData.Name] {
+		dependency, ok := modules[imported.module]
+		if !ok {
+			dependency = &data.Dependency{SourceFile: imported.module}
+			modules[imported.module] = dependency
+			fileData.AddDependency(dependency)
+		}
+		dependency.TypeNames = append(dependency.TypeNames, imported.name)
+	}
+	for _, dependency := range modules {
+		sort.Strings(dependency.TypeNames)
+	}
+}
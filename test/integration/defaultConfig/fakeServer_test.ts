@@ -0,0 +1,99 @@
+import { expect } from "chai";
+import { CounterService, createCounterServiceRouter } from "./service.pb";
+
+describe("test fake server", () => {
+  const fake = createCounterServiceRouter({
+    increment: (req) => ({ result: (req.counter ?? 0) + 1 }),
+    failingIncrement: () => {
+      throw { status: 503, code: 14, message: "this increment does not work" };
+    },
+    streamingIncrements: (req, notify) => {
+      for (let i = 1; i <= 5; i++) {
+        notify({ result: (req.counter ?? 0) + i });
+      }
+    },
+    httpGet: (req) => ({ result: (req.numToIncrease ?? 0) + 1 }),
+    httpPostWithNestedBodyPath: (req) => ({
+      postResult: (req.a ?? 0) + (req.req?.b ?? 0),
+    }),
+    httpGetWithURLSearchParams: (req) => ({
+      urlSearchParamsResult:
+        (req.a ?? 0) +
+        (req.b?.b ?? 0) +
+        (req.c ?? []).reduce((acc, c) => acc + c, 0) +
+        (req.d?.d ?? 0),
+    }),
+    httpPostWithPathParamPattern: (req) => ({
+      postResult: `${req.a} ${req.c}`,
+    }),
+  });
+
+  it("unary request", async () => {
+    const result = await CounterService.Increment(
+      { counter: 199 },
+      { fetch: fake }
+    );
+    expect(result.result).to.equal(200);
+  });
+
+  it("failing unary request", async () => {
+    try {
+      await CounterService.FailingIncrement({ counter: 199 }, { fetch: fake });
+      expect.fail("expected call to throw");
+    } catch (e) {
+      expect(e).to.have.property("message", "this increment does not work");
+      expect(e).to.have.property("code", 14);
+    }
+  });
+
+  it("unimplemented request", async () => {
+    try {
+      await CounterService.HTTPPatch({ a: 1, c: 2 }, { fetch: fake });
+      expect.fail("expected call to throw");
+    } catch (e) {
+      expect(e).to.have.property("code", 12);
+    }
+  });
+
+  it("streaming request", async () => {
+    const response = [] as number[];
+    await CounterService.StreamingIncrements(
+      { counter: 1 },
+      (resp) => response.push(resp.result!),
+      { fetch: fake }
+    );
+    expect(response).to.deep.equal([2, 3, 4, 5, 6]);
+  });
+
+  it("path parameters", async () => {
+    const result = await CounterService.HTTPGet(
+      { numToIncrease: 10 },
+      { fetch: fake }
+    );
+    expect(result.result).to.equal(11);
+  });
+
+  it("nested body path", async () => {
+    const result = await CounterService.HTTPPostWithNestedBodyPath(
+      { a: 10, req: { b: 15 } },
+      { fetch: fake }
+    );
+    expect(result.postResult).to.equal(25);
+  });
+
+  it("query parameters", async () => {
+    const result = await CounterService.HTTPGetWithURLSearchParams(
+      { a: 10, b: { b: 0 }, c: [23, 33], d: { d: 12 } },
+      { fetch: fake }
+    );
+    expect(result.urlSearchParamsResult).to.equal(78);
+  });
+
+  it("path parameter patterns", async () => {
+    const result = await CounterService.HTTPPostWithPathParamPattern(
+      { a: "first/hello", c: "foo/bar/baz", req: { b: 1 } },
+      { fetch: fake }
+    );
+    expect(result.postResult).to.equal("first/hello foo/bar/baz");
+  });
+});
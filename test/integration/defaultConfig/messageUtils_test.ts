@@ -0,0 +1,60 @@
+import { expect } from "chai";
+import {
+  cloneOptionalFieldsResponse,
+  equalsOptionalFieldsResponse,
+  mergeOptionalFieldsResponse,
+} from "./service.pb";
+
+describe("test message utils", () => {
+  it("equal messages", () => {
+    expect(
+      equalsOptionalFieldsResponse(
+        { definedStr: "a", definedMsg: { str: "b" } },
+        { definedStr: "a", definedMsg: { str: "b" } }
+      )
+    ).to.be.true;
+  });
+
+  it("absent fields equal their zero value", () => {
+    expect(equalsOptionalFieldsResponse({ zeroStr: "" }, {})).to.be.true;
+    expect(equalsOptionalFieldsResponse({}, { zeroNumber: 0 })).to.be.true;
+  });
+
+  it("messages which differ", () => {
+    expect(
+      equalsOptionalFieldsResponse({ definedStr: "a" }, { definedStr: "b" })
+    ).to.be.false;
+    expect(equalsOptionalFieldsResponse({ definedStr: "a" }, {})).to.be.false;
+    expect(equalsOptionalFieldsResponse({}, { definedNumber: 1 })).to.be.false;
+    expect(
+      equalsOptionalFieldsResponse(
+        { definedMsg: { str: "a" } },
+        { definedMsg: { str: "b" } }
+      )
+    ).to.be.false;
+  });
+
+  it("optional fields track presence", () => {
+    expect(equalsOptionalFieldsResponse({ zeroOptStr: "" }, {})).to.be.false;
+  });
+
+  it("clone", () => {
+    const msg = { definedStr: "a", definedMsg: { str: "b" } };
+    const copy = cloneOptionalFieldsResponse(msg);
+    expect(copy).to.deep.equal(msg);
+    expect(copy.definedMsg === msg.definedMsg).to.be.false;
+  });
+
+  it("merge", () => {
+    expect(
+      mergeOptionalFieldsResponse(
+        { definedStr: "a", definedMsg: { str: "b" } },
+        { definedNumber: 1, definedMsg: { optStr: "c" } }
+      )
+    ).to.deep.equal({
+      definedStr: "a",
+      definedNumber: 1,
+      definedMsg: { str: "b", optStr: "c" },
+    });
+  });
+});
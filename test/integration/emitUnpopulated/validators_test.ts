@@ -0,0 +1,52 @@
+import { expect } from "chai";
+import {
+  CounterService,
+  isOptionalFieldsResponse,
+  isMessageWithMultipleOneOfFields,
+} from "./service.pb";
+import { enableResponseValidation, ValidationError } from "./fetch.pb";
+
+describe("test runtime validators", () => {
+  before(() => enableResponseValidation());
+  after(() => enableResponseValidation(false));
+
+  it("accepts gateway responses", async () => {
+    const result = await CounterService.HTTPGetWithOptionalFields(
+      {},
+      { pathPrefix: "http://localhost:8081" }
+    );
+    expect(isOptionalFieldsResponse(result)).to.equal(true);
+  });
+
+  it("rejects missing zero values", () => {
+    expect(isOptionalFieldsResponse({ emptyStr: "" })).to.equal(false);
+  });
+
+  it("rejects multiple oneof fields", () => {
+    expect(isMessageWithMultipleOneOfFields({ id: "1", a: "a", c: 1 })).to.equal(
+      true
+    );
+    expect(
+      isMessageWithMultipleOneOfFields({ id: "1", a: "a", b: "b", c: 1 })
+    ).to.equal(false);
+  });
+
+  it("rejects invalid responses", async () => {
+    try {
+      await CounterService.Increment(
+        { counter: 1 },
+        {
+          fetch: () =>
+            Promise.resolve(new Response(JSON.stringify({ result: "2" }))),
+        }
+      );
+      expect.fail("expected call to throw");
+    } catch (e) {
+      expect(e).to.be.instanceOf(ValidationError);
+      expect(e).to.have.property(
+        "message",
+        "UnaryResponse.result: expected number, got string"
+      );
+    }
+  });
+});